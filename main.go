@@ -10,11 +10,19 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	ebitenAudio "github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/okayama-daiki/tetris/tetris/audio"
-	"github.com/okayama-daiki/tetris/tetris/game"
+	"github.com/okayama-daiki/tetris/tetris/engine"
+	"github.com/okayama-daiki/tetris/tetris/replay"
+	"github.com/okayama-daiki/tetris/tetris/scene"
 )
 
 var cpuprofile = flag.String("cpuprofile", "", "write cpu profile to `file`")
 var memprofile = flag.String("memprofile", "", "write memory profile to `file`")
+var seed = flag.Int64("seed", 0, "generate minos from `seed` (0 means a random seed for every game)")
+var record = flag.String("record", "", "write the replay of the last run to `file`")
+var play = flag.String("replay", "", "play back the replay from `file` instead of the keyboard")
+var mode = flag.String("mode", "", "start the `mode` (endless, marathon, sprint, ultra, cheese or survival) without the menu")
+var kicks = flag.String("kicks", "", "use the SRS kick tables in the JSON `file` instead of the standard ones")
+var attack = flag.String("attack", "", "use the attack table in the JSON `file` instead of the standard one")
 
 func main() {
 	flag.Parse()
@@ -32,16 +40,47 @@ func main() {
 
 	ebiten.SetWindowSize(600, 600)
 	ebiten.SetWindowTitle("EbiTetris")
+	// Keep updating while unfocused so that the game can be paused automatically
+	ebiten.SetRunnableOnUnfocused(true)
 
 	audioPlayer, err := audio.NewPlayer(ebitenAudio.NewContext(44100))
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	var game = game.NewGame(audioPlayer)
-	if err := ebiten.RunGame(game); err != nil {
+	manager := scene.NewManager(audioPlayer)
+	manager.Seed = *seed
+	manager.ReplayPath = *record
+	if *kicks != "" {
+		if manager.Kicks, err = engine.LoadKickTables(*kicks); err != nil {
+			log.Fatal("could not load kick tables: ", err)
+		}
+	}
+	if *attack != "" {
+		if manager.Attack, err = engine.LoadAttackTable(*attack); err != nil {
+			log.Fatal("could not load attack table: ", err)
+		}
+	}
+	switch {
+	case *play != "":
+		r, err := replay.Load(*play)
+		if err != nil {
+			log.Fatal("could not load replay: ", err)
+		}
+		if err := manager.PlayReplay(r); err != nil {
+			log.Fatal(err)
+		}
+	case *mode != "":
+		if err := manager.StartGame(*mode); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := ebiten.RunGame(manager); err != nil {
 		log.Fatal(err)
 	}
+	if err := manager.Close(); err != nil {
+		log.Fatal("could not save replay: ", err)
+	}
 
 	if *memprofile != "" {
 		f, err := os.Create(*memprofile)
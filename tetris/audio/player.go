@@ -17,6 +17,7 @@ type Player struct {
 	rotateAudioPlayer   *audio.Player
 	moveAudioPlayer     *audio.Player
 	holdAudioPlayer     *audio.Player
+	levelUpAudioPlayer  *audio.Player
 }
 
 func NewPlayer(audioContext *audio.Context) (*Player, error) {
@@ -44,6 +45,7 @@ func NewPlayer(audioContext *audio.Context) (*Player, error) {
 		rotateAudioPlayer:   load(se.Rotate),
 		moveAudioPlayer:     load(se.Move),
 		holdAudioPlayer:     load(se.Hold),
+		levelUpAudioPlayer:  audioContext.NewPlayerFromBytes(levelUpSound(audioContext.SampleRate())),
 	}
 
 	if len(errors) > 0 {
@@ -82,6 +84,29 @@ func (p *Player) PlayHardDrop() {
 	_play(p.hardDropAudioPlayer)
 }
 
+func (p *Player) PlayLevelUp() {
+	_play(p.levelUpAudioPlayer)
+}
+
+// SetMusicVolume sets the volume of the BGM in [0, 1]
+func (p *Player) SetMusicVolume(volume float64) {
+	p.audioPlayer.SetVolume(volume)
+}
+
+// SetSoundVolume sets the volume of the sound effects in [0, 1]
+func (p *Player) SetSoundVolume(volume float64) {
+	for _, player := range []*audio.Player{
+		p.hardDropAudioPlayer,
+		p.clearAudioPlayer,
+		p.rotateAudioPlayer,
+		p.moveAudioPlayer,
+		p.holdAudioPlayer,
+		p.levelUpAudioPlayer,
+	} {
+		player.SetVolume(volume)
+	}
+}
+
 func (p *Player) Update() {
 	if p.audioPlayer.IsPlaying() {
 		return
@@ -0,0 +1,37 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+const (
+	LEVEL_UP_NOTE_SECONDS = 0.07
+	LEVEL_UP_VOLUME       = 0.3
+	// How fast each note fades out per second
+	LEVEL_UP_DECAY = 12
+)
+
+// Frequencies of the arpeggio of the level up chime: C5, E5, G5 and C6
+var levelUpNotes = []float64{523.25, 659.25, 783.99, 1046.50}
+
+// Synthesize the level up chime as 16-bit stereo PCM since there is no recorded sound for it.
+// The last note rings three times as long as the others.
+func levelUpSound(sampleRate int) []byte {
+	noteSamples := int(LEVEL_UP_NOTE_SECONDS * float64(sampleRate))
+	pcm := make([]byte, 0, (len(levelUpNotes)+2)*noteSamples*4)
+	for i, frequency := range levelUpNotes {
+		samples := noteSamples
+		if i == len(levelUpNotes)-1 {
+			samples *= 3
+		}
+		for n := range samples {
+			t := float64(n) / float64(sampleRate)
+			v := LEVEL_UP_VOLUME * math.Exp(-LEVEL_UP_DECAY*t) * math.Sin(2*math.Pi*frequency*t)
+			sample := uint16(int16(v * math.MaxInt16))
+			pcm = binary.LittleEndian.AppendUint16(pcm, sample) // Left
+			pcm = binary.LittleEndian.AppendUint16(pcm, sample) // Right
+		}
+	}
+	return pcm
+}
@@ -0,0 +1,80 @@
+package engine
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// AttackTable decides the garbage rows sent by a line clear.
+// The lists are indexed by the number of lines or the combo, and the last entry is used for the larger ones.
+type AttackTable struct {
+	Clear        []int `json:"clear"`         // Line clears without a spin
+	TSpin        []int `json:"tspin"`         // Spins which are not mini
+	MiniTSpin    []int `json:"mini_tspin"`    // Mini spins
+	BackToBack   int   `json:"back_to_back"`  // Added to the back-to-back clears
+	Combo        []int `json:"combo"`         // Added to the clears in a combo
+	PerfectClear int   `json:"perfect_clear"` // Added to the perfect clears
+}
+
+// The attack table of the guideline
+var DEFAULT_ATTACK_TABLE = AttackTable{
+	Clear:        []int{0, 0, 1, 2, 4},
+	TSpin:        []int{0, 2, 4, 6},
+	MiniTSpin:    []int{0, 0, 1},
+	BackToBack:   1,
+	Combo:        []int{0, 0, 1, 1, 2, 2, 3, 3, 4, 4, 4, 5},
+	PerfectClear: 10,
+}
+
+// Return the i-th entry of the list, or the last one if the list is shorter
+func attackAt(list []int, i int) int {
+	if len(list) == 0 {
+		return 0
+	}
+	return list[min(i, len(list)-1)]
+}
+
+// Attack returns the garbage rows sent by the clear, which is 0 without lines
+func (t *AttackTable) Attack(result ClearResult) int {
+	if result.Lines == 0 {
+		return 0
+	}
+	var attack int
+	switch result.Spin {
+	case SpinMini:
+		attack = attackAt(t.MiniTSpin, result.Lines)
+	case SpinFull:
+		attack = attackAt(t.TSpin, result.Lines)
+	default:
+		attack = attackAt(t.Clear, result.Lines)
+	}
+	if result.BackToBack {
+		attack += t.BackToBack
+	}
+	attack += attackAt(t.Combo, result.Combo)
+	if result.PerfectClear {
+		attack += t.PerfectClear
+	}
+	return attack
+}
+
+// ReadAttackTable reads an attack table written in JSON such as
+//
+//	{"clear": [0, 0, 1, 2, 4], "tspin": [0, 2, 4, 6], "mini_tspin": [0, 0, 1], "back_to_back": 1, "combo": [0, 0, 1], "perfect_clear": 10}
+func ReadAttackTable(r io.Reader) (*AttackTable, error) {
+	table := &AttackTable{}
+	if err := json.NewDecoder(r).Decode(table); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+func LoadAttackTable(name string) (*AttackTable, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadAttackTable(f)
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAttack(t *testing.T) {
+	tests := []struct {
+		result ClearResult
+		want   int
+	}{
+		{ClearResult{Lines: 0, Spin: SpinFull}, 0},
+		{ClearResult{Lines: 1}, 0},
+		{ClearResult{Lines: 2}, 1},
+		{ClearResult{Lines: 4}, 4},
+		{ClearResult{Lines: 4, BackToBack: true}, 5},
+		{ClearResult{Lines: 2, Spin: SpinFull}, 4},
+		{ClearResult{Lines: 3, Spin: SpinFull, BackToBack: true}, 7},
+		{ClearResult{Lines: 2, Spin: SpinMini}, 1},
+		{ClearResult{Lines: 1, Combo: 4}, 2},
+		{ClearResult{Lines: 1, Combo: 20}, 5},
+		{ClearResult{Lines: 4, PerfectClear: true}, 14},
+	}
+
+	for _, tt := range tests {
+		if got := DEFAULT_ATTACK_TABLE.Attack(tt.result); got != tt.want {
+			t.Errorf("%v: got %d, want %d", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestReadAttackTable(t *testing.T) {
+	table, err := ReadAttackTable(strings.NewReader(`{"clear": [0, 1, 2, 3, 4], "combo": [0, 1]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := table.Attack(ClearResult{Lines: 1, Combo: 3, Spin: SpinFull}); got != 1 {
+		t.Errorf("got %d, want 1 without the spin table", got)
+	}
+	if got := table.Attack(ClearResult{Lines: 2, Combo: 3}); got != 3 {
+		t.Errorf("got %d, want 3", got)
+	}
+}
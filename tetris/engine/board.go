@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"image/color"
+	"slices"
+)
+
+var (
+	WALL_COLOR = color.RGBA{108, 122, 137, 255}
+)
+
+type Board [OUTER_HEIGHT][OUTER_WIDTH]color.Color
+
+func NewBoard() Board {
+	board := Board{}
+	for y := range OUTER_HEIGHT - 1 {
+		board[y][0] = WALL_COLOR
+		board[y][OUTER_WIDTH-1] = WALL_COLOR
+	}
+	for x := range OUTER_WIDTH {
+		board[OUTER_HEIGHT-1][x] = WALL_COLOR
+	}
+	return board
+}
+
+// Return true if the y-th row is filled
+func (b *Board) IsFilled(y int) bool {
+	for _, cell := range b[y] {
+		if cell == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Return true if the cell is out of the board or already filled
+func (b *Board) IsOccupied(x, y int) bool {
+	if y < 0 || y >= OUTER_HEIGHT || x < 0 || x >= OUTER_WIDTH {
+		return true
+	}
+	return b[y][x] != nil
+}
+
+// Return true if no blocks remain in the playfield
+func (b *Board) IsEmpty() bool {
+	for y := range OUTER_HEIGHT - SENTINEL_SIZE {
+		for x := SENTINEL_SIZE; x < OUTER_WIDTH-SENTINEL_SIZE; x++ {
+			if b[y][x] != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Return true if the mino is collided with the board
+func (b *Board) isCollided(mino AbstractMino) bool {
+	for dy := range len(mino.Shape()) {
+		for dx := range len(mino.Shape()[dy]) {
+			if mino.Shape()[dy][dx] == 0 {
+				continue
+			}
+			ny, nx := mino.Y()+dy, mino.X()+dx
+			if ny < 0 || ny >= OUTER_HEIGHT || nx < 0 || nx >= OUTER_WIDTH {
+				return true
+			}
+			if b[ny][nx] != nil || b[ny][nx] == WALL_COLOR {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Return true if every block of the mino is above the visible field
+func isAboveSkyline(mino AbstractMino) bool {
+	for dy := range len(mino.Shape()) {
+		for dx := range len(mino.Shape()[dy]) {
+			if mino.Shape()[dy][dx] != 0 && mino.Y()+dy >= MARGIN {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Write the color of mino to the board at each position
+func (b *Board) Fix(mino AbstractMino) {
+	for dy := range len(mino.Shape()) {
+		for dx := range len(mino.Shape()[dy]) {
+			if mino.Shape()[dy][dx] == 0 {
+				continue
+			}
+			b[mino.Y()+dy][mino.X()+dx] = mino.Color()
+		}
+	}
+}
+
+// Clear the filled lines and return them with their colors
+func (b *Board) ClearLines() (clearedLines []int, clearedColors [][OUTER_WIDTH]color.Color) {
+	clearedLines, clearedColors = b.EraseLines()
+	b.Collapse(clearedLines)
+	return
+}
+
+// Empty the filled lines and return them with their colors, leaving the lines above where they are until `Collapse`
+func (b *Board) EraseLines() (erasedLines []int, erasedColors [][OUTER_WIDTH]color.Color) {
+	erasedLines = []int{}
+	erasedColors = [][OUTER_WIDTH]color.Color{}
+
+	for y := MARGIN + INNER_HEIGHT - SENTINEL_SIZE; y >= 0; y-- {
+		if !b.IsFilled(y) {
+			continue
+		}
+		erasedLines = append(erasedLines, y)
+		erasedColors = append(erasedColors, b[y])
+		for x := SENTINEL_SIZE; x < OUTER_WIDTH-SENTINEL_SIZE; x++ {
+			b[y][x] = nil
+		}
+	}
+	return
+}
+
+// Remove the erased lines and move the lines above them down
+func (b *Board) Collapse(erasedLines []int) {
+	newBoard := NewBoard()
+	removed := 0
+	for y := MARGIN + INNER_HEIGHT - SENTINEL_SIZE; y >= 0; y-- {
+		if slices.Contains(erasedLines, y) {
+			removed++
+			continue
+		}
+		newBoard[y+removed] = b[y]
+	}
+	*b = newBoard
+}
+
+// Push the lines up by a garbage row with a hole at the column `hole` at the bottom of the playfield.
+// Return true if blocks are pushed out of the top of the board.
+func (b *Board) AddGarbage(hole int) bool {
+	overflow := false
+	for x := SENTINEL_SIZE; x < OUTER_WIDTH-SENTINEL_SIZE; x++ {
+		if b[0][x] != nil {
+			overflow = true
+		}
+	}
+	bottom := MARGIN + INNER_HEIGHT - SENTINEL_SIZE
+	copy(b[:bottom], b[1:bottom+1])
+	for x := SENTINEL_SIZE; x < OUTER_WIDTH-SENTINEL_SIZE; x++ {
+		b[bottom][x] = GARBAGE_COLOR
+	}
+	b[bottom][hole] = nil
+	return overflow
+}
+
+// Return the number of rows which still have garbage blocks
+func (b *Board) CountGarbageRows() int {
+	n := 0
+	for y := range OUTER_HEIGHT - SENTINEL_SIZE {
+		if slices.Contains(b[y][:], color.Color(GARBAGE_COLOR)) {
+			n++
+		}
+	}
+	return n
+}
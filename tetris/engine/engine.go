@@ -0,0 +1,498 @@
+package engine
+
+import (
+	"cmp"
+	"image/color"
+	"iter"
+)
+
+const (
+	INNER_HEIGHT  = 20
+	INNER_WIDTH   = 10
+	SENTINEL_SIZE = 1
+	MARGIN        = 3
+	OUTER_HEIGHT  = MARGIN + INNER_HEIGHT + SENTINEL_SIZE
+	OUTER_WIDTH   = SENTINEL_SIZE + INNER_WIDTH + SENTINEL_SIZE
+)
+
+const (
+	MAX_LEVEL = 110
+)
+
+// Frames per second the engine is designed to be stepped at
+const (
+	TPS = 60
+)
+
+type EventKind int
+
+const (
+	EventMove EventKind = iota
+	EventRotate
+	EventHold
+	EventHardDrop
+	EventLock
+	EventLineClear
+	EventLevelUp
+	EventAttack  // Garbage rows are sent to the other players
+	EventGarbage // The received garbage rows are inserted
+	EventTopOut
+	EventFinish
+)
+
+// The reason why the game is over
+type TopOut int
+
+const (
+	TopOutNone     TopOut = iota
+	TopOutBlockOut        // A new mino overlaps the blocks on the board
+	TopOutLockOut         // A mino is locked entirely above the visible field
+	TopOutGarbage         // Blocks are pushed out of the top of the board by garbage
+)
+
+func (t TopOut) String() string {
+	return [...]string{"", "Block Out", "Lock Out", "Top Out"}[t]
+}
+
+// Phase is the state of the engine from the lock of a mino to the spawn of the next one
+type Phase int
+
+const (
+	PhaseFalling   Phase = iota // A mino is falling under the control of the player
+	PhaseLineClear              // The cleared lines are left empty until the lines above fall (line clear delay)
+	PhaseEntry                  // The next mino is about to appear (ARE)
+)
+
+// An event notifies the caller of something that happened during a frame
+//   - `Lines` and `Colors` are only set for `EventLineClear`
+//   - `Attack` is the number of garbage rows, which is only set for `EventAttack` and `EventGarbage`
+type Event struct {
+	Kind   EventKind
+	Lines  []int
+	Colors [][OUTER_WIDTH]color.Color
+	Attack int
+}
+
+// Engine holds the whole state of a game and advances it frame by frame.
+// It knows nothing about windows, keyboards or audio, so it can be driven by anything that produces an `Input`.
+type Engine struct {
+	PutPieces       int
+	ClearedLines    int
+	FrameCount      int
+	MinoFrameCount  int
+	Level           int
+	LevelLines      int // Lines counted toward the next level, see `LevelGoal`
+	Board           Board
+	CurrentMino     AbstractMino
+	HoldingMino     HoldingMino
+	CurrentLockDown LockDown
+	MinoBag         MinoBag
+	Score           Score
+	Mode            Mode
+	Finished        bool // True if the game is over or the goal of the mode is reached
+	TopOut          TopOut
+	Handling        Handling
+	Rules           Rules
+	RotationSystem  RotationSystem
+	Gravity         GravityCurve
+	Phase           Phase
+	AttackTable     *AttackTable
+	Incoming        []IncomingGarbage // The received garbage from the oldest, see `ReceiveGarbage`
+	SentLines       int
+
+	pressDurations [ActionCount]int
+	events         []Event
+	phaseFrames    int     // Frames left until the end of the phase
+	fallen         float64 // Rows accumulated by the gravity which the mino has not fallen yet
+	erasedLines    []int   // The lines cleared during the line clear delay
+	buffered       Input   // The rotations and hold pressed during the phases, applied to the next mino by IRS and IHS
+	garbage        *GarbageGenerator
+	levelGoal      string // One of `LevelGoalNames`, which only the marathon chooses
+	lastKick       int    // Index of the kick used by the last rotation, or `NO_ROTATION`
+	repeatCutUntil int    // The auto repeat is suspended until this frame by `DASCut` or `DCD`
+}
+
+func NewEngine(seed int64, mode Mode, rules Rules) *Engine {
+	e := &Engine{
+		Mode:            mode,
+		MinoBag:         NewMinoBag(seed, rules.Randomizer),
+		Board:           NewBoard(),
+		HoldingMino:     HoldingMino{Available: true},
+		CurrentLockDown: NewLockDown(rules),
+		Level:           1,
+		levelGoal:       LEVEL_GOAL_FIXED,
+		Score:           NewScore(),
+		Handling:        DefaultHandling(),
+		Rules:           rules,
+		RotationSystem:  NewRotationSystem(rules),
+		Gravity:         GravityCurveByName(rules.Gravity),
+		AttackTable:     cmp.Or(rules.Attack, &DEFAULT_ATTACK_TABLE),
+		garbage:         NewGarbageGenerator(seed^GARBAGE_SEED_SALT, GARBAGE_BATCH_MESSINESS),
+		lastKick:        NO_ROTATION,
+	}
+	if starter, ok := mode.(Starter); ok {
+		starter.Start(e)
+	}
+	e.spawn(e.MinoBag.Next())
+	return e
+}
+
+// Step advances the game by one frame with the given input and returns the events occurred in the frame
+// Nothing happens once the game is finished.
+func (e *Engine) Step(input Input) []Event {
+	e.events = nil
+	if e.Finished {
+		return e.events
+	}
+	for a := range ActionCount {
+		if input.Has(a) {
+			e.pressDurations[a]++
+		} else {
+			e.pressDurations[a] = 0
+		}
+	}
+
+	e.FrameCount++
+
+	if e.Phase != PhaseFalling {
+		e.wait()
+	}
+	if e.Phase == PhaseFalling && !e.Finished {
+		e.play()
+	}
+
+	if !e.Finished && e.Mode.Update(e) {
+		e.Finished = true
+		e.emit(Event{Kind: EventFinish})
+	}
+
+	return e.events
+}
+
+// Handle the actions and the gravity of the falling mino
+func (e *Engine) play() {
+	e.MinoFrameCount++
+
+	// Hold
+	if e.isJustPressed(ActionHold) && e.HoldingMino.Available {
+		e.hold()
+		if e.Finished {
+			return
+		}
+	}
+
+	// Hard drop
+	if e.isJustPressed(ActionHardDrop) {
+		e.emit(Event{Kind: EventHardDrop})
+		e.Score.Drop(e.Ghost().Y()-e.CurrentMino.Y(), true, e.Level)
+		e.lock()
+		if e.Finished || e.Phase != PhaseFalling {
+			return
+		}
+	}
+
+	// Move Left
+	e.shift(ActionMoveLeft, AbstractMino.MoveLeft)
+
+	// Move Right
+	e.shift(ActionMoveRight, AbstractMino.MoveRight)
+
+	// Rotate right
+	if e.isJustPressed(ActionRotateRight) {
+		e.tryRotate(e.RotationSystem.Rotate(&e.Board, e.CurrentMino, true))
+	}
+
+	// Rotate left
+	if e.isJustPressed(ActionRotateLeft) {
+		e.tryRotate(e.RotationSystem.Rotate(&e.Board, e.CurrentMino, false))
+	}
+
+	// Rotate 180
+	if e.isJustPressed(ActionRotate180) {
+		e.tryRotate(e.rotate180())
+	}
+
+	// Soft drop
+	gravity := e.Gravity(e.Level)
+	softDropping := e.pressDurations[ActionSoftDrop] > 0
+	if softDropping {
+		if e.Handling.SoftDropFactor == INFINITE_SOFT_DROP {
+			ghostMino := e.Ghost()
+			if cells := ghostMino.Y() - e.CurrentMino.Y(); cells > 0 {
+				e.Score.Drop(cells, false, e.Level)
+				e.fall(ghostMino)
+			}
+		} else {
+			gravity = min(gravity*float64(e.Handling.SoftDropFactor), MAX_GRAVITY)
+		}
+	}
+
+	if e.CurrentLockDown.Update(e.Board.isCollided(e.CurrentMino.MoveDown())) {
+		e.lock()
+	} else {
+		e.applyGravity(gravity, softDropping)
+	}
+}
+
+// Let the mino fall by the gravity in rows per frame, carrying the fraction of a row over to the next frames.
+// The rows fallen by the soft drop are scored.
+func (e *Engine) applyGravity(gravity float64, softDropping bool) {
+	for e.fallen += gravity; e.fallen >= 1-GRAVITY_EPSILON; e.fallen-- {
+		nextMino := e.CurrentMino.MoveDown()
+		if e.Board.isCollided(nextMino) {
+			e.fallen = 0
+			return
+		}
+		if softDropping {
+			e.Score.Drop(1, false, e.Level)
+		}
+		e.fall(nextMino)
+	}
+}
+
+// Count down the phase between the minos, buffering the rotations and hold pressed meanwhile.
+// The keys pressed in the frame the phase ends are not buffered since they act on the new mino in the frame anyway.
+func (e *Engine) wait() {
+	e.phaseFrames--
+	if e.phaseFrames <= 0 {
+		e.endPhase()
+		return
+	}
+	for _, a := range []Action{ActionRotateRight, ActionRotateLeft, ActionRotate180, ActionHold} {
+		if e.isJustPressed(a) {
+			e.buffered = e.buffered.With(a)
+		}
+	}
+}
+
+// Start the phase lasting `frames`, or go through it at once if it lasts no frames
+func (e *Engine) startPhase(phase Phase, frames int) {
+	e.Phase, e.phaseFrames = phase, frames
+	if frames <= 0 {
+		e.endPhase()
+	}
+}
+
+func (e *Engine) endPhase() {
+	switch e.Phase {
+	case PhaseLineClear:
+		e.Board.Collapse(e.erasedLines)
+		e.erasedLines = nil
+		e.startPhase(PhaseEntry, e.Rules.ARE)
+	case PhaseEntry:
+		e.Phase = PhaseFalling
+		e.spawnNext()
+	}
+}
+
+func (e *Engine) IsGameOver() bool {
+	return e.TopOut != TopOutNone
+}
+
+func (e *Engine) topOut(reason TopOut) {
+	e.TopOut = reason
+	e.Finished = true
+	e.emit(Event{Kind: EventTopOut})
+}
+
+// Ghost returns the current mino dropped as far as possible
+func (e *Engine) Ghost() AbstractMino {
+	ghostMino := e.CurrentMino
+	for ; !e.Board.isCollided(ghostMino.MoveDown()); ghostMino = ghostMino.MoveDown() {
+	}
+	return ghostMino
+}
+
+func (e *Engine) emit(event Event) {
+	e.events = append(e.events, event)
+}
+
+func (e *Engine) isJustPressed(a Action) bool {
+	return e.pressDurations[a] == 1
+}
+
+// Return true if the action is auto repeated, that is, held longer than `DAS` and not suspended
+func (e *Engine) isAutoRepeating(a Action) bool {
+	return e.pressDurations[a] > e.Handling.DAS && e.FrameCount > e.repeatCutUntil
+}
+
+// Return true if the action should take effect in this frame, taking the auto repeat into account
+func (e *Engine) isRepeated(a Action) bool {
+	if e.pressDurations[a] == 1 {
+		return true
+	}
+	if !e.isAutoRepeating(a) {
+		return false
+	}
+	return e.Handling.ARR == 0 || (e.pressDurations[a]-e.Handling.DAS-1)%e.Handling.ARR == 0
+}
+
+// Suspend the auto repeat for the given frames
+func (e *Engine) cutRepeat(frames int) {
+	// No cut at all for 0, since the cut lasts through the current frame otherwise
+	if frames <= 0 {
+		return
+	}
+	e.repeatCutUntil = max(e.repeatCutUntil, e.FrameCount+frames)
+}
+
+// Move the mino horizontally by `step` if the action takes effect, as far as possible if `ARR` is 0
+func (e *Engine) shift(a Action, step func(AbstractMino) AbstractMino) {
+	if !e.isRepeated(a) {
+		return
+	}
+	instant := e.Handling.ARR == 0 && e.isAutoRepeating(a)
+	for e.move(step(e.CurrentMino)) && instant {
+	}
+}
+
+// Return true if the moved mino is accepted
+func (e *Engine) move(nextMino AbstractMino) bool {
+	if e.Board.isCollided(nextMino) {
+		return false
+	}
+	e.emit(Event{Kind: EventMove})
+	e.CurrentLockDown.Move()
+	e.CurrentMino = nextMino
+	e.lastKick = NO_ROTATION
+	return true
+}
+
+// Move the mino down to `nextMino`, which the caller has checked to be free
+func (e *Engine) fall(nextMino AbstractMino) {
+	e.CurrentLockDown.Fall(nextMino.Y())
+	e.CurrentMino = nextMino
+	e.lastKick = NO_ROTATION
+}
+
+// Return true if the rotated mino is accepted
+func (e *Engine) rotate(nextMino AbstractMino, kick int) bool {
+	if e.Board.isCollided(nextMino) {
+		return false
+	}
+	e.emit(Event{Kind: EventRotate})
+	e.CurrentLockDown.Move()
+	e.CurrentMino = nextMino
+	e.lastKick = kick
+	e.cutRepeat(e.Handling.DCD)
+	return true
+}
+
+// Rotate the mino to the first candidate which fits
+func (e *Engine) tryRotate(candidates iter.Seq2[int, AbstractMino]) {
+	for kick, nextMino := range candidates {
+		if e.rotate(nextMino, kick) {
+			return
+		}
+	}
+}
+
+// Yield the candidates of the 180 rotation, whose kicks are all reported as `ROTATION_180`
+func (e *Engine) rotate180() iter.Seq2[int, AbstractMino] {
+	return func(yield func(int, AbstractMino) bool) {
+		for _, nextMino := range e.CurrentMino.Kick(2, Kick180Table(e.Rules.Kick180)) {
+			if !yield(ROTATION_180, nextMino) {
+				return
+			}
+		}
+	}
+}
+
+// Drop the current mino to the bottom, fix it to the board and spawn the next one
+func (e *Engine) lock() {
+	ghostMino := e.Ghost()
+	if ghostMino.Y() != e.CurrentMino.Y() {
+		e.lastKick = NO_ROTATION
+	}
+	e.CurrentMino = ghostMino
+	spin := e.Board.Spin(e.CurrentMino, e.lastKick)
+	if e.CurrentMino.Type() != MinoTypeT && !e.Rules.AllSpin {
+		spin = SpinNone
+	}
+	lockedOut := isAboveSkyline(e.CurrentMino)
+	e.Board.Fix(e.CurrentMino)
+	e.emit(Event{Kind: EventLock})
+	clearedLines, clearedColors := e.Board.EraseLines()
+	if len(clearedLines) > 0 {
+		e.ClearedLines += len(clearedLines)
+		e.emit(Event{Kind: EventLineClear, Lines: clearedLines, Colors: clearedColors})
+	}
+	result := e.Score.Clear(e.CurrentMino.Type(), len(clearedLines), spin, len(clearedLines) > 0 && e.Board.IsEmpty(), e.Level)
+	e.levelUp(result)
+	e.PutPieces++
+	e.lastKick = NO_ROTATION
+	if lockedOut {
+		e.topOut(TopOutLockOut)
+		return
+	}
+	if !e.updateGarbage(result) {
+		e.topOut(TopOutGarbage)
+		return
+	}
+	if len(clearedLines) > 0 {
+		e.erasedLines = clearedLines
+		e.startPhase(PhaseLineClear, e.Rules.LineClearDelay)
+	} else {
+		e.startPhase(PhaseEntry, e.Rules.ARE)
+	}
+}
+
+// Spawn the next mino after the last one is locked
+func (e *Engine) spawnNext() {
+	e.HoldingMino.Available = true
+	e.MinoFrameCount = 0
+	e.spawn(e.MinoBag.Next())
+	e.buffered = 0
+}
+
+// Swap the current mino with the held one, or with the next one if nothing is held
+func (e *Engine) hold() {
+	if e.HoldingMino.AbstractMino == nil {
+		e.HoldingMino.AbstractMino = e.MinoBag.Next()
+	}
+	e.emit(Event{Kind: EventHold})
+	held := e.HoldingMino.AbstractMino
+	e.HoldingMino.AbstractMino = e.CurrentMino.Initialize()
+	e.HoldingMino.Available = false
+	e.spawn(held)
+}
+
+// Spawn the mino, applying the initial hold (IHS) and rotation (IRS) of the keys held at the spawn if the rules allow.
+// Only the keys held since before this frame or buffered are taken, since those just pressed act later in the frame anyway.
+func (e *Engine) spawn(mino AbstractMino) {
+	e.CurrentMino = e.RotationSystem.Spawn(mino)
+	if e.Rules.IHS && e.HoldingMino.Available && e.isInitial(ActionHold) {
+		e.hold()
+		return
+	}
+	if e.Rules.IRS {
+		e.rotateInitially()
+	}
+	if e.Board.isCollided(e.CurrentMino) {
+		e.topOut(TopOutBlockOut)
+		return
+	}
+	e.fallen = 0
+	e.CurrentLockDown.Reset()
+	if e.Gravity(e.Level) >= MAX_GRAVITY {
+		e.fall(e.Ghost())
+	}
+	e.cutRepeat(e.Handling.DASCut)
+}
+
+// Rotate the spawned mino by the first rotation key held, keeping it as it is if the rotation does not fit
+func (e *Engine) rotateInitially() {
+	switch {
+	case e.isInitial(ActionRotateRight):
+		e.tryRotate(e.RotationSystem.Rotate(&e.Board, e.CurrentMino, true))
+	case e.isInitial(ActionRotateLeft):
+		e.tryRotate(e.RotationSystem.Rotate(&e.Board, e.CurrentMino, false))
+	case e.isInitial(ActionRotate180):
+		e.tryRotate(e.rotate180())
+	}
+}
+
+// Return true if the action is held since before this frame or buffered during the phases
+func (e *Engine) isInitial(a Action) bool {
+	return e.pressDurations[a] > 1 || e.buffered.Has(a)
+}
@@ -0,0 +1,298 @@
+package engine
+
+import (
+	"testing"
+)
+
+func countEvents(events []Event, kind EventKind) int {
+	n := 0
+	for _, event := range events {
+		if event.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestHardDrop(t *testing.T) {
+	e := NewEngine(0, Endless{}, DefaultRules())
+	e.CurrentMino = NewMinoI().Initialize()
+
+	// Leave 4 holes just under the I mino
+	bottom := OUTER_HEIGHT - SENTINEL_SIZE - 1
+	for x := SENTINEL_SIZE; x < SENTINEL_SIZE+INNER_WIDTH; x++ {
+		if x < 4 || x > 7 {
+			e.Board[bottom][x] = WALL_COLOR
+		}
+	}
+
+	events := e.Step(Input(0).With(ActionHardDrop))
+
+	if countEvents(events, EventHardDrop) != 1 || countEvents(events, EventLock) != 1 {
+		t.Errorf("got %v, want a hard drop and a lock", events)
+	}
+	if countEvents(events, EventLineClear) != 1 {
+		t.Fatalf("got %v, want a line clear", events)
+	}
+	if e.PutPieces != 1 || e.ClearedLines != 1 {
+		t.Errorf("got %d pieces and %d lines, want 1 and 1", e.PutPieces, e.ClearedLines)
+	}
+	for x := SENTINEL_SIZE; x < SENTINEL_SIZE+INNER_WIDTH; x++ {
+		if e.Board[bottom][x] != nil {
+			t.Errorf("got %v at (%d, %d), want empty", e.Board[bottom][x], x, bottom)
+		}
+	}
+}
+
+func TestHold(t *testing.T) {
+	e := NewEngine(0, Endless{}, DefaultRules())
+	e.CurrentMino = NewMinoT().Initialize()
+
+	e.Step(Input(0).With(ActionHold))
+	if e.HoldingMino.AbstractMino == nil || e.HoldingMino.Color() != PURPLE || e.HoldingMino.Available {
+		t.Fatalf("got %v, want an unavailable T mino in hold", e.HoldingMino)
+	}
+
+	// Holding again is not allowed until the next mino
+	e.Step(Input(0))
+	e.Step(Input(0).With(ActionHold))
+	if e.HoldingMino.Color() != PURPLE {
+		t.Errorf("got %v, want a T mino kept in hold", e.HoldingMino)
+	}
+}
+
+func TestIRS(t *testing.T) {
+	for _, irs := range []bool{true, false} {
+		rules := DefaultRules()
+		rules.IRS = irs
+		e := NewEngine(0, Endless{}, rules)
+
+		// The rotation key pressed before the hard drop is held when the next mino spawns
+		e.Step(Input(0).With(ActionRotateRight))
+		e.Step(Input(0).With(ActionRotateRight).With(ActionHardDrop))
+		want := Angle0
+		if irs {
+			want = Angle90
+		}
+		if got := e.CurrentMino.Angle(); got != want {
+			t.Errorf("IRS %v: got angle %d, want %d", irs, got, want)
+		}
+	}
+}
+
+func TestIHS(t *testing.T) {
+	for _, ihs := range []bool{true, false} {
+		rules := DefaultRules()
+		rules.IHS = ihs
+		e := NewEngine(0, Endless{}, rules)
+
+		// The hold key pressed before the hard drop is held when the next mino spawns
+		e.Step(Input(0).With(ActionHold))
+		held := e.HoldingMino.Type()
+		next := e.MinoBag.Sniff(1)[0].Type()
+		e.Step(Input(0).With(ActionHold).With(ActionHardDrop))
+		if ihs && (e.HoldingMino.Type() != next || e.CurrentMino.Type() != held || e.HoldingMino.Available) {
+			t.Errorf("got %v in hold and %v, want %v in hold and %v", e.HoldingMino.Type(), e.CurrentMino.Type(), next, held)
+		}
+		if !ihs && (e.HoldingMino.Type() != held || e.CurrentMino.Type() != next || !e.HoldingMino.Available) {
+			t.Errorf("got %v in hold and %v, want %v in hold and %v", e.HoldingMino.Type(), e.CurrentMino.Type(), held, next)
+		}
+	}
+}
+
+func TestARE(t *testing.T) {
+	rules := DefaultRules()
+	rules.ARE = 5
+	e := NewEngine(0, Endless{}, rules)
+	next := e.MinoBag.Sniff(1)[0].Type()
+
+	e.Step(Input(0).With(ActionHardDrop))
+	// A rotation tapped during ARE is buffered and applied to the next mino by IRS
+	e.Step(Input(0).With(ActionRotateRight))
+	for frame := 2; frame < rules.ARE; frame++ {
+		e.Step(Input(0).With(ActionMoveLeft))
+		if e.Phase != PhaseEntry {
+			t.Fatalf("got phase %d at frame %d, want %d", e.Phase, frame, PhaseEntry)
+		}
+	}
+	e.Step(Input(0))
+	if e.Phase != PhaseFalling || e.CurrentMino.Type() != next {
+		t.Fatalf("got phase %d and %v, want %d and %v", e.Phase, e.CurrentMino.Type(), PhaseFalling, next)
+	}
+	if e.CurrentMino.Angle() != Angle90 {
+		t.Errorf("got angle %d, want %d by the buffered rotation", e.CurrentMino.Angle(), Angle90)
+	}
+}
+
+func TestLineClearDelay(t *testing.T) {
+	rules := DefaultRules()
+	rules.LineClearDelay = 3
+	e := NewEngine(0, Endless{}, rules)
+	e.CurrentMino = NewMinoI().Initialize()
+
+	// Fill the bottom line except under the I mino and put a block on the line above
+	bottom := OUTER_HEIGHT - SENTINEL_SIZE - 1
+	for x := SENTINEL_SIZE; x < SENTINEL_SIZE+INNER_WIDTH; x++ {
+		if x < 4 || x > 7 {
+			e.Board[bottom][x] = WALL_COLOR
+		}
+	}
+	e.Board[bottom-1][SENTINEL_SIZE] = WALL_COLOR
+
+	events := e.Step(Input(0).With(ActionHardDrop))
+	if countEvents(events, EventLineClear) != 1 || e.Phase != PhaseLineClear {
+		t.Fatalf("got %v in phase %d, want a line clear in phase %d", events, e.Phase, PhaseLineClear)
+	}
+	for range rules.LineClearDelay - 1 {
+		e.Step(Input(0))
+		if e.Board[bottom-1][SENTINEL_SIZE] == nil || e.Board[bottom][SENTINEL_SIZE] != nil {
+			t.Fatalf("got the lines collapsed during the line clear delay")
+		}
+	}
+	e.Step(Input(0))
+	if e.Board[bottom][SENTINEL_SIZE] == nil || e.Board[bottom-1][SENTINEL_SIZE] != nil {
+		t.Errorf("got the lines not collapsed after the line clear delay")
+	}
+	if e.Phase != PhaseFalling {
+		t.Errorf("got phase %d, want %d since ARE is 0", e.Phase, PhaseFalling)
+	}
+}
+
+func TestAutoRepeat(t *testing.T) {
+	e := NewEngine(0, Endless{}, DefaultRules())
+	e.CurrentMino = NewMinoO().Initialize()
+	x := e.CurrentMino.X()
+
+	moved := 0
+	for range e.Handling.DAS {
+		moved += countEvents(e.Step(Input(0).With(ActionMoveLeft)), EventMove)
+	}
+	if moved != 1 {
+		t.Errorf("got %d moves before the auto repeat, want 1", moved)
+	}
+	for range e.Handling.ARR {
+		moved += countEvents(e.Step(Input(0).With(ActionMoveLeft)), EventMove)
+	}
+	if moved != 2 || e.CurrentMino.X() != x-2 {
+		t.Errorf("got %d moves and x = %d, want 2 and %d", moved, e.CurrentMino.X(), x-2)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	a, b := NewEngine(42, Endless{}, DefaultRules()), NewEngine(42, Endless{}, DefaultRules())
+	for i := range 50 {
+		a.Step(Input(0).With(ActionHardDrop))
+		b.Step(Input(0).With(ActionHardDrop))
+		if a.CurrentMino.Color() != b.CurrentMino.Color() {
+			t.Fatalf("got different minos at %d-th piece with the same seed", i)
+		}
+	}
+}
+
+func TestTopOut(t *testing.T) {
+	tests := []struct {
+		name string
+		fill func(b *Board)
+		want TopOut
+	}{
+		{
+			"block out",
+			func(b *Board) {
+				// Every mino spawns over these blocks
+				b[1][4], b[1][5] = WALL_COLOR, WALL_COLOR
+			},
+			TopOutBlockOut,
+		},
+		{
+			"lock out",
+			func(b *Board) {
+				for y := MARGIN; y < OUTER_HEIGHT-SENTINEL_SIZE; y++ {
+					for x := SENTINEL_SIZE + 1; x < SENTINEL_SIZE+INNER_WIDTH; x++ {
+						b[y][x] = WALL_COLOR
+					}
+				}
+			},
+			TopOutLockOut,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEngine(0, Endless{}, DefaultRules())
+			e.CurrentMino = NewMinoI().Initialize().MoveRight().MoveRight()
+			tt.fill(&e.Board)
+
+			events := e.Step(Input(0).With(ActionHardDrop))
+			if countEvents(events, EventTopOut) != 1 || e.TopOut != tt.want || !e.Finished {
+				t.Errorf("got %v, want %v", e.TopOut, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandling(t *testing.T) {
+	// Step with the input held for the frames and return the x of the O mino
+	hold := func(e *Engine, input Input, frames int) int {
+		for range frames {
+			e.Step(input)
+		}
+		return e.CurrentMino.X()
+	}
+	left := Input(0).With(ActionMoveLeft)
+	wall := NewMinoO().Initialize()
+	for b := NewBoard(); !b.isCollided(wall.MoveLeft()); wall = wall.MoveLeft() {
+	}
+
+	t.Run("instant ARR", func(t *testing.T) {
+		e := NewEngine(0, Endless{}, DefaultRules())
+		e.Handling.ARR = 0
+		e.CurrentMino = NewMinoO().Initialize()
+		if x := hold(e, left, e.Handling.DAS); x != e.CurrentMino.Initialize().X()-1 {
+			t.Errorf("got x = %d before the auto repeat, want a single move", x)
+		}
+		if x := hold(e, left, 1); x != wall.X() {
+			t.Errorf("got x = %d, want %d", x, wall.X())
+		}
+	})
+
+	t.Run("no DAS cut", func(t *testing.T) {
+		e := NewEngine(0, Endless{}, DefaultRules())
+		e.Handling.ARR, e.Handling.DASCut = 0, 0
+		e.CurrentMino = NewMinoO().Initialize()
+		hold(e, left, e.Handling.DAS+1)
+		e.Step(left.With(ActionHardDrop))
+		want := e.CurrentMino
+		for ; !e.Board.isCollided(want.MoveLeft()); want = want.MoveLeft() {
+		}
+		if e.PutPieces != 1 || e.CurrentMino.X() != want.X() {
+			t.Errorf("got x = %d in the spawn frame, want %d", e.CurrentMino.X(), want.X())
+		}
+	})
+
+	t.Run("DCD", func(t *testing.T) {
+		e := NewEngine(0, Endless{}, DefaultRules())
+		e.Handling.DCD = 5
+		e.CurrentMino = NewMinoO().Initialize()
+		hold(e, left, e.Handling.DAS)
+		x := hold(e, left.With(ActionRotateRight), 1)
+		if got := hold(e, left, e.Handling.DCD); got != x {
+			t.Errorf("got x = %d during DCD, want %d", got, x)
+		}
+		if got := hold(e, left, e.Handling.ARR); got != x-1 {
+			t.Errorf("got x = %d after DCD, want %d", got, x-1)
+		}
+	})
+
+	t.Run("infinite soft drop", func(t *testing.T) {
+		e := NewEngine(0, Endless{}, DefaultRules())
+		e.Handling.SoftDropFactor = INFINITE_SOFT_DROP
+		e.Step(Input(0).With(ActionSoftDrop))
+		ghost := e.Ghost()
+		if e.CurrentMino.Y() != ghost.Y() || e.Score.Points != ghost.Y()-e.CurrentMino.Initialize().Y() {
+			t.Errorf("got y = %d and %d points, want the mino on the bottom", e.CurrentMino.Y(), e.Score.Points)
+		}
+		if e.PutPieces != 0 {
+			t.Errorf("got %d pieces, want the mino not locked", e.PutPieces)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"image/color"
+	"math/rand"
+)
+
+var (
+	GARBAGE_COLOR = color.RGBA{150, 150, 150, 255}
+)
+
+const (
+	// Messiness of the holes between the batches of the received garbage, which always moves the hole
+	GARBAGE_BATCH_MESSINESS = 1
+	MAX_GARBAGE_DELAY       = 10
+	// Mixed into the seed of the game so that the holes of the received garbage do not follow the minos or the cheese
+	GARBAGE_SEED_SALT = 0x3c6ef372fe94f82b
+)
+
+// GarbageGenerator decides the column of the hole in each garbage row.
+// The hole stays in the same column and moves to another one with the probability `Messiness` for each row,
+// so 0 makes a single well and 1 makes a new column every row.
+// It draws only from the source given on construction, so the same seed always generates the same garbage.
+type GarbageGenerator struct {
+	Messiness float64
+	rand      *rand.Rand
+	hole      int // The column of the last hole, or -1 before the first row
+}
+
+func NewGarbageGenerator(seed int64, messiness float64) *GarbageGenerator {
+	return &GarbageGenerator{Messiness: messiness, rand: rand.New(rand.NewSource(seed)), hole: -1}
+}
+
+// Next returns the column of the hole of the next row on the board
+func (g *GarbageGenerator) Next() int {
+	switch {
+	case g.hole < 0:
+		g.hole = SENTINEL_SIZE + g.rand.Intn(INNER_WIDTH)
+	case g.rand.Float64() < g.Messiness:
+		// Skip the current column so that the hole always moves
+		g.hole = SENTINEL_SIZE + (g.hole-SENTINEL_SIZE+1+g.rand.Intn(INNER_WIDTH-1))%INNER_WIDTH
+	}
+	return g.hole
+}
+
+// IncomingGarbage is a batch of garbage rows received at once, which share the column of the hole
+type IncomingGarbage struct {
+	Lines int
+	Delay int // Minos to lock before the rows are inserted
+}
+
+// ReceiveGarbage queues the garbage rows sent by another player.
+// They are inserted after `Rules.GarbageDelay` minos unless they are canceled by attacks before that.
+func (e *Engine) ReceiveGarbage(lines int) {
+	if lines <= 0 {
+		return
+	}
+	e.Incoming = append(e.Incoming, IncomingGarbage{Lines: lines, Delay: e.Rules.GarbageDelay})
+}
+
+// Return the number of garbage rows waiting to be inserted
+func (e *Engine) IncomingLines() int {
+	n := 0
+	for _, garbage := range e.Incoming {
+		n += garbage.Lines
+	}
+	return n
+}
+
+// Handle the garbage for a locked mino.
+//   - The attack of the clear cancels the incoming garbage from the oldest, and the rest is sent by `EventAttack`
+//   - The garbage whose delay is over is inserted unless the mino clears lines, each batch with a new column of the hole
+//   - The delay of the other garbage counts down
+//
+// Return false if the garbage pushes blocks out of the board.
+func (e *Engine) updateGarbage(result ClearResult) bool {
+	attack := e.AttackTable.Attack(result)
+	for attack > 0 && len(e.Incoming) > 0 {
+		canceled := min(attack, e.Incoming[0].Lines)
+		attack -= canceled
+		e.Incoming[0].Lines -= canceled
+		if e.Incoming[0].Lines == 0 {
+			e.Incoming = e.Incoming[1:]
+		}
+	}
+	if attack > 0 {
+		e.SentLines += attack
+		e.emit(Event{Kind: EventAttack, Attack: attack})
+	}
+
+	incoming := e.Incoming[:0]
+	inserted := 0
+	for _, garbage := range e.Incoming {
+		if garbage.Delay > 0 || result.Lines > 0 {
+			garbage.Delay = max(garbage.Delay-1, 0)
+			incoming = append(incoming, garbage)
+			continue
+		}
+		hole := e.garbage.Next()
+		for range garbage.Lines {
+			if e.Board.AddGarbage(hole) {
+				return false
+			}
+		}
+		inserted += garbage.Lines
+	}
+	e.Incoming = incoming
+	if inserted > 0 {
+		e.emit(Event{Kind: EventGarbage, Attack: inserted})
+	}
+	return true
+}
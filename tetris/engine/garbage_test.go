@@ -0,0 +1,104 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestGarbageGenerator(t *testing.T) {
+	tests := []struct {
+		messiness float64
+		wantMoves int
+	}{
+		{0, 0},
+		{1, 99},
+	}
+
+	for _, tt := range tests {
+		g := NewGarbageGenerator(0, tt.messiness)
+		moves, last := 0, g.Next()
+		for range 99 {
+			hole := g.Next()
+			if hole < SENTINEL_SIZE || hole >= SENTINEL_SIZE+INNER_WIDTH {
+				t.Fatalf("got the hole at %d, want in the playfield", hole)
+			}
+			if hole != last {
+				moves++
+			}
+			last = hole
+		}
+		if moves != tt.wantMoves {
+			t.Errorf("messiness %v: got %d moves, want %d", tt.messiness, moves, tt.wantMoves)
+		}
+	}
+}
+
+func TestAddGarbage(t *testing.T) {
+	b := NewBoard()
+	bottom := MARGIN + INNER_HEIGHT - SENTINEL_SIZE
+	b[bottom][SENTINEL_SIZE] = WALL_COLOR
+
+	if b.AddGarbage(SENTINEL_SIZE + 3) {
+		t.Fatalf("got overflow, want none")
+	}
+	if b[bottom-1][SENTINEL_SIZE] != WALL_COLOR {
+		t.Errorf("got %v, want the block pushed up", b[bottom-1][SENTINEL_SIZE])
+	}
+	if b[bottom][SENTINEL_SIZE+3] != nil || b[bottom][SENTINEL_SIZE] != GARBAGE_COLOR {
+		t.Errorf("got %v, want a garbage row with the hole at %d", b[bottom], SENTINEL_SIZE+3)
+	}
+	if got := b.CountGarbageRows(); got != 1 {
+		t.Errorf("got %d garbage rows, want 1", got)
+	}
+
+	b[0][SENTINEL_SIZE] = WALL_COLOR
+	if !b.AddGarbage(SENTINEL_SIZE) {
+		t.Errorf("got no overflow, want overflow")
+	}
+}
+
+func TestGarbageQueue(t *testing.T) {
+	rules := DefaultRules()
+	rules.GarbageDelay = 1
+	e := NewEngine(0, Endless{}, rules)
+	e.ReceiveGarbage(3)
+	e.ReceiveGarbage(2)
+
+	// A double sends 1 row, which cancels 1 of the oldest batch, and the delay counts down
+	if !e.updateGarbage(ClearResult{Lines: 2}) {
+		t.Fatalf("got top out, want none")
+	}
+	if got := e.IncomingLines(); got != 4 || countEvents(e.events, EventAttack) != 0 {
+		t.Fatalf("got %d incoming rows, want 4 with nothing sent", got)
+	}
+
+	// A back-to-back tetris cancels the 4 rows left and sends 1 row
+	e.events = nil
+	e.updateGarbage(ClearResult{Lines: 4, BackToBack: true})
+	if got := e.IncomingLines(); got != 0 {
+		t.Fatalf("got %d incoming rows, want 0", got)
+	}
+	if got := countEvents(e.events, EventAttack); got != 1 || e.SentLines != 1 {
+		t.Errorf("got %d attacks of %d rows, want 1 of 1 row", got, e.SentLines)
+	}
+
+	// The garbage is inserted after the delay by a mino which clears no lines
+	e.ReceiveGarbage(2)
+	e.updateGarbage(ClearResult{})
+	if got := e.Board.CountGarbageRows(); got != 0 {
+		t.Fatalf("got %d garbage rows during the delay, want 0", got)
+	}
+	e.events = nil
+	e.updateGarbage(ClearResult{})
+	if got := e.Board.CountGarbageRows(); got != 2 || countEvents(e.events, EventGarbage) != 1 {
+		t.Errorf("got %d garbage rows, want 2", got)
+	}
+}
+
+func TestGarbageTopOut(t *testing.T) {
+	e := NewEngine(0, Endless{}, DefaultRules())
+	e.ReceiveGarbage(OUTER_HEIGHT)
+	e.Step(Input(0).With(ActionHardDrop))
+	if !e.Finished || e.TopOut != TopOutGarbage {
+		t.Errorf("got %v, want %v", e.TopOut, TopOutGarbage)
+	}
+}
@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"math"
+)
+
+const (
+	GRAVITY_GUIDELINE = "guideline"
+	GRAVITY_CLASSIC   = "classic"
+	GRAVITY_NES       = "nes"
+	GRAVITY_20G       = "20g"
+)
+
+// Names of the gravity curves in the order shown to players
+var GravityNames = []string{GRAVITY_GUIDELINE, GRAVITY_CLASSIC, GRAVITY_NES, GRAVITY_20G}
+
+const (
+	// The gravity in rows per frame at which minos fall to the bottom at once, which is as high as the field
+	MAX_GRAVITY = 20
+	// Tolerance of the accumulated gravity so that a mino falls after exactly n frames at 1/n G despite rounding errors
+	GRAVITY_EPSILON = 1e-9
+)
+
+// GravityCurve returns the gravity at the level in rows per frame, which can be fractional.
+// The gravity of `MAX_GRAVITY` (20G) drops minos to the bottom as soon as they spawn.
+type GravityCurve func(level int) float64
+
+// Frames per row of the NES Tetris from level 0 to 29, the last of which lasts for the higher levels
+var nesFramesPerRow = []int{48, 43, 38, 33, 28, 23, 18, 13, 8, 6, 5, 5, 5, 4, 4, 4, 3, 3, 3, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 1}
+
+// Gravity curves
+//   - guideline: a row takes (0.8-(level-1)*0.007)^(level-1) seconds, reaching 20G at level 19
+//   - classic: a row takes 0.8-(level-1)*0.05 seconds in whole frames, at least a frame, which the game used to have
+//   - nes: the frames per row of the NES Tetris, where level 1 here is level 0 there
+//   - 20g: always 20G for practicing the highest speed of the Master modes
+var gravityCurves = map[string]GravityCurve{
+	GRAVITY_GUIDELINE: func(level int) float64 {
+		seconds := math.Pow(0.8-float64(level-1)*0.007, float64(level-1))
+		return min(1/(seconds*TPS), MAX_GRAVITY)
+	},
+	GRAVITY_CLASSIC: func(level int) float64 {
+		return 1 / float64(max(int((0.8-float64(level-1)*0.05)*TPS), 1))
+	},
+	GRAVITY_NES: func(level int) float64 {
+		return 1 / float64(nesFramesPerRow[min(level-1, len(nesFramesPerRow)-1)])
+	},
+	GRAVITY_20G: func(level int) float64 {
+		return MAX_GRAVITY
+	},
+}
+
+// Return the gravity curve named `name`, or the guideline one if there is no such curve
+func GravityCurveByName(name string) GravityCurve {
+	if curve, ok := gravityCurves[name]; ok {
+		return curve
+	}
+	return gravityCurves[GRAVITY_GUIDELINE]
+}
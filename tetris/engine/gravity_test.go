@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGravityCurve(t *testing.T) {
+	tests := []struct {
+		name  string
+		level int
+		want  float64
+	}{
+		{GRAVITY_GUIDELINE, 1, 1.0 / 60},
+		{GRAVITY_GUIDELINE, 2, 1 / (0.793 * 60)},
+		{GRAVITY_GUIDELINE, 19, MAX_GRAVITY},
+		{GRAVITY_CLASSIC, 1, 1.0 / 48},
+		{GRAVITY_CLASSIC, 20, 1},
+		{GRAVITY_NES, 1, 1.0 / 48},
+		{GRAVITY_NES, 30, 1},
+		{GRAVITY_NES, MAX_LEVEL, 1},
+		{GRAVITY_20G, 1, MAX_GRAVITY},
+		{"unknown", 1, 1.0 / 60},
+	}
+
+	for _, tt := range tests {
+		if got := GravityCurveByName(tt.name)(tt.level); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s at level %d: got %v, want %v", tt.name, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestFractionalGravity(t *testing.T) {
+	for _, name := range []string{GRAVITY_GUIDELINE, GRAVITY_CLASSIC} {
+		rules := DefaultRules()
+		rules.Gravity = name
+		e := NewEngine(0, Endless{}, rules)
+		y := e.CurrentMino.Y()
+		frames := int(math.Round(1 / e.Gravity(1)))
+
+		for range frames - 1 {
+			e.Step(Input(0))
+		}
+		if e.CurrentMino.Y() != y {
+			t.Errorf("%s: got the mino fallen before %d frames", name, frames)
+		}
+		e.Step(Input(0))
+		if e.CurrentMino.Y() != y+1 {
+			t.Errorf("%s: got y = %d after %d frames, want %d", name, e.CurrentMino.Y(), frames, y+1)
+		}
+	}
+}
+
+func Test20G(t *testing.T) {
+	rules := DefaultRules()
+	rules.Gravity = GRAVITY_20G
+	e := NewEngine(0, Endless{}, rules)
+	if e.CurrentMino.Y() != e.Ghost().Y() {
+		t.Fatalf("got y = %d, want the mino spawned on the bottom at %d", e.CurrentMino.Y(), e.Ghost().Y())
+	}
+
+	// The next mino spawns on the stack as well
+	e.Step(Input(0).With(ActionHardDrop))
+	if e.CurrentMino.Y() != e.Ghost().Y() {
+		t.Errorf("got y = %d, want the next mino spawned on the stack at %d", e.CurrentMino.Y(), e.Ghost().Y())
+	}
+}
@@ -0,0 +1,32 @@
+package engine
+
+const (
+	DEFAULT_DAS              = 9
+	DEFAULT_ARR              = 2
+	DEFAULT_SOFT_DROP_FACTOR = 20
+
+	// Soft drop factor which drops the mino to the bottom at once
+	INFINITE_SOFT_DROP = 0
+)
+
+// Handling decides how the held inputs take effect. All durations are in frames.
+//   - `DAS` is the delay before the auto repeat of left and right starts
+//   - `ARR` is the interval of the auto repeat, and 0 moves the mino to the wall at once
+//   - `SoftDropFactor` is how many times faster the soft drop is than the gravity, or `INFINITE_SOFT_DROP`
+//   - `DASCut` suspends the auto repeat after a new mino spawns so that it does not fly to the wall right away
+//   - `DCD` (DAS cut delay) suspends the auto repeat after a rotation
+type Handling struct {
+	DAS            int `json:"das"`
+	ARR            int `json:"arr"`
+	SoftDropFactor int `json:"soft_drop_factor"`
+	DASCut         int `json:"das_cut"`
+	DCD            int `json:"dcd"`
+}
+
+func DefaultHandling() Handling {
+	return Handling{
+		DAS:            DEFAULT_DAS,
+		ARR:            DEFAULT_ARR,
+		SoftDropFactor: DEFAULT_SOFT_DROP_FACTOR,
+	}
+}
@@ -0,0 +1,27 @@
+package engine
+
+// Action is a player operation which the engine understands
+type Action int
+
+const (
+	ActionMoveLeft Action = iota
+	ActionMoveRight
+	ActionSoftDrop
+	ActionHardDrop
+	ActionRotateRight
+	ActionRotateLeft
+	ActionHold
+	ActionRotate180
+	ActionCount
+)
+
+// Input is a snapshot of the actions held during a single frame
+type Input uint16
+
+func (i Input) Has(a Action) bool {
+	return i&(1<<a) != 0
+}
+
+func (i Input) With(a Action) Input {
+	return i | 1<<a
+}
@@ -0,0 +1,165 @@
+package engine
+
+import (
+	"encoding/json"
+	"io"
+	"iter"
+	"os"
+)
+
+// Offset is a translation of a mino in cells.
+// Y grows upward as in the published kick tables, while the rows of `Board` grow downward.
+type Offset struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// KickTable lists the offsets tried in order for each angle before a rotation
+type KickTable [4][]Offset
+
+// KickTables holds the kick tables of clockwise and counterclockwise rotations for each mino.
+// The minos without a table rotate only in place.
+type KickTables struct {
+	Right map[MinoType]KickTable `json:"right"`
+	Left  map[MinoType]KickTable `json:"left"`
+}
+
+// The kick table of the minos which rotate only in place
+var noKicks = KickTable{{{0, 0}}, {{0, 0}}, {{0, 0}}, {{0, 0}}}
+
+// The kick tables of SRS, which are the same for J, L, S, T and Z
+var (
+	srsRightKicks = KickTable{
+		{{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}}, // 0 -> R
+		{{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}},     // R -> 2
+		{{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}},    // 2 -> L
+		{{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}},  // L -> 0
+	}
+	srsLeftKicks = KickTable{
+		{{0, 0}, {1, 0}, {1, 1}, {0, -2}, {1, -2}},    // 0 -> L
+		{{0, 0}, {1, 0}, {1, -1}, {0, 2}, {1, 2}},     // R -> 0
+		{{0, 0}, {-1, 0}, {-1, 1}, {0, -2}, {-1, -2}}, // 2 -> R
+		{{0, 0}, {-1, 0}, {-1, -1}, {0, 2}, {-1, 2}},  // L -> 2
+	}
+	srsRightKicksI = KickTable{
+		{{0, 0}, {-2, 0}, {1, 0}, {-2, -1}, {1, 2}}, // 0 -> R
+		{{0, 0}, {-1, 0}, {2, 0}, {-1, 2}, {2, -1}}, // R -> 2
+		{{0, 0}, {2, 0}, {-1, 0}, {2, 1}, {-1, -2}}, // 2 -> L
+		{{0, 0}, {1, 0}, {-2, 0}, {1, -2}, {-2, 1}}, // L -> 0
+	}
+	srsLeftKicksI = KickTable{
+		{{0, 0}, {-1, 0}, {2, 0}, {-1, 2}, {2, -1}}, // 0 -> L
+		{{0, 0}, {2, 0}, {-1, 0}, {2, 1}, {-1, -2}}, // R -> 0
+		{{0, 0}, {1, 0}, {-2, 0}, {1, -2}, {-2, 1}}, // 2 -> R
+		{{0, 0}, {-2, 0}, {1, 0}, {-2, -1}, {1, 2}}, // L -> 2
+	}
+)
+
+// The kick tables of the guideline SRS
+var SRS_KICK_TABLES = KickTables{
+	Right: map[MinoType]KickTable{
+		MinoTypeI: srsRightKicksI,
+		MinoTypeJ: srsRightKicks,
+		MinoTypeL: srsRightKicks,
+		MinoTypeS: srsRightKicks,
+		MinoTypeT: srsRightKicks,
+		MinoTypeZ: srsRightKicks,
+	},
+	Left: map[MinoType]KickTable{
+		MinoTypeI: srsLeftKicksI,
+		MinoTypeJ: srsLeftKicks,
+		MinoTypeL: srsLeftKicks,
+		MinoTypeS: srsLeftKicks,
+		MinoTypeT: srsLeftKicks,
+		MinoTypeZ: srsLeftKicks,
+	},
+}
+
+// ReadKickTables reads kick tables written in JSON such as
+//
+//	{"right": {"T": [[{"x": 0, "y": 0}, {"x": -1, "y": 0}], ...]}, "left": {...}}
+func ReadKickTables(r io.Reader) (*KickTables, error) {
+	tables := &KickTables{}
+	if err := json.NewDecoder(r).Decode(tables); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+func LoadKickTables(name string) (*KickTables, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadKickTables(f)
+}
+
+const (
+	KICK_180_NONE     = "none"
+	KICK_180_SRS_PLUS = "srs+"
+	KICK_180_TETRIO   = "tetrio"
+)
+
+// Names of the kick tables of the 180 rotation in the order shown to players
+var Kick180Names = []string{KICK_180_NONE, KICK_180_SRS_PLUS, KICK_180_TETRIO}
+
+// Kick tables of the 180 rotation, which are shared by all minos
+//   - none: the mino rotates only in place
+//   - srs+: a single kick toward the side the mino pointed to, that is, up from Angle0 and right from Angle90
+//   - tetrio: the table of TETR.IO which also tries the diagonals and two cells up from the sides
+var kick180Tables = map[string]KickTable{
+	KICK_180_NONE: noKicks,
+	KICK_180_SRS_PLUS: {
+		{{0, 0}, {0, 1}},
+		{{0, 0}, {1, 0}},
+		{{0, 0}, {0, -1}},
+		{{0, 0}, {-1, 0}},
+	},
+	KICK_180_TETRIO: {
+		{{0, 0}, {0, 1}, {1, 1}, {-1, 1}, {1, 0}, {-1, 0}},
+		{{0, 0}, {1, 0}, {1, 2}, {1, 1}, {0, 2}, {0, 1}},
+		{{0, 0}, {0, -1}, {-1, -1}, {1, -1}, {-1, 0}, {1, 0}},
+		{{0, 0}, {-1, 0}, {-1, 2}, {-1, 1}, {0, 2}, {0, 1}},
+	},
+}
+
+// Return the kick table of the 180 rotation named `name`, or the one without kicks if there is no such table
+func Kick180Table(name string) KickTable {
+	if table, ok := kick180Tables[name]; ok {
+		return table
+	}
+	return kick180Tables[KICK_180_NONE]
+}
+
+// Return the mino moved by the offset
+func translate(mino AbstractMino, offset Offset) AbstractMino {
+	for range max(offset.X, 0) {
+		mino = mino.MoveRight()
+	}
+	for range max(-offset.X, 0) {
+		mino = mino.MoveLeft()
+	}
+	for range max(offset.Y, 0) {
+		mino = mino.MoveUp()
+	}
+	for range max(-offset.Y, 0) {
+		mino = mino.MoveDown()
+	}
+	return mino
+}
+
+// Kick yields the minos rotated clockwise by `turns` quarters and moved by each offset of the table with the index of the kick
+func (m BaseMino) Kick(turns int, kicks KickTable) iter.Seq2[int, AbstractMino] {
+	return func(yield func(int, AbstractMino) bool) {
+		for i, offset := range kicks[m.angle] {
+			rotated := m
+			rotated.angle = (m.angle + Angle(turns)) % 4
+			rotated.x += offset.X
+			rotated.y -= offset.Y
+			if !yield(i, rotated) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package engine
+
+const (
+	LEVEL_GOAL_FIXED    = "fixed"
+	LEVEL_GOAL_VARIABLE = "variable"
+)
+
+// Names of the level goals in the order shown to players
+var LevelGoalNames = []string{LEVEL_GOAL_FIXED, LEVEL_GOAL_VARIABLE}
+
+const (
+	LINES_PER_LEVEL = 10 // Lines to clear for each level under the fixed goal
+	// Awarded lines to earn for each level under the variable goal, which are multiplied by the level
+	VARIABLE_GOAL_LINES = 5
+)
+
+// LevelGoal returns the lines to count in the current level to go up to the next one.
+//   - fixed: `LINES_PER_LEVEL` cleared lines at every level
+//   - variable: `VARIABLE_GOAL_LINES` times the level in awarded lines, which are more for the difficult clears (see `ClearResult.AwardedLines`)
+func (e *Engine) LevelGoal() int {
+	if e.levelGoal == LEVEL_GOAL_VARIABLE {
+		return VARIABLE_GOAL_LINES * e.Level
+	}
+	return LINES_PER_LEVEL
+}
+
+// Count the lines of the clear toward the goal and go up the levels reached
+func (e *Engine) levelUp(result ClearResult) {
+	if e.levelGoal == LEVEL_GOAL_VARIABLE {
+		e.LevelLines += result.AwardedLines()
+	} else {
+		e.LevelLines += result.Lines
+	}
+	for e.Level < MAX_LEVEL && e.LevelLines >= e.LevelGoal() {
+		e.LevelLines -= e.LevelGoal()
+		e.Level++
+		e.emit(Event{Kind: EventLevelUp})
+	}
+}
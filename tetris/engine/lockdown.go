@@ -0,0 +1,176 @@
+package engine
+
+import (
+	"math"
+)
+
+const (
+	LOCK_DOWN_EXTENDED = "extended"
+	LOCK_DOWN_INFINITE = "infinite"
+	LOCK_DOWN_STEP     = "step"
+	LOCK_DOWN_CLASSIC  = "classic"
+)
+
+const (
+	DEFAULT_LOCK_DELAY  = 30 // Frames for which a mino stays on the stack before it locks
+	DEFAULT_LOCK_RESETS = 15 // Moves and rotations which reset the lock delay under the extended placement
+)
+
+// Names of the lock down policies in the order shown to players
+var LockDownNames = []string{LOCK_DOWN_EXTENDED, LOCK_DOWN_INFINITE, LOCK_DOWN_STEP, LOCK_DOWN_CLASSIC}
+
+// LockDown decides when the falling mino locks after it lands on the stack.
+// The lock delay runs only while the mino is on the stack, and each policy differs in what resets it.
+type LockDown interface {
+	Name() string
+	// Reset starts over for a new mino
+	Reset()
+	// Move is called when the mino is moved or rotated
+	Move()
+	// Fall is called when the mino falls to the row `y`
+	Fall(y int)
+	// Update is called every frame with whether the mino is on the stack, and returns true if the mino should lock
+	Update(grounded bool) bool
+}
+
+var lockDowns = map[string]func(delay, resets int) LockDown{
+	LOCK_DOWN_EXTENDED: func(delay, resets int) LockDown {
+		return &ExtendedPlacement{lockTimer: lockTimer{Delay: delay}, Resets: resets, lowest: math.MinInt}
+	},
+	LOCK_DOWN_INFINITE: func(delay, resets int) LockDown { return &InfinitePlacement{lockTimer{Delay: delay}} },
+	LOCK_DOWN_STEP:     func(delay, resets int) LockDown { return &StepReset{lockTimer{Delay: delay}} },
+	LOCK_DOWN_CLASSIC:  func(delay, resets int) LockDown { return &ClassicLockDown{lockTimer{Delay: delay}} },
+}
+
+// Return the lock down policy of the rules, or the extended placement if there is no such policy
+func NewLockDown(rules Rules) LockDown {
+	newLockDown, ok := lockDowns[rules.LockDown]
+	if !ok {
+		newLockDown = lockDowns[LOCK_DOWN_EXTENDED]
+	}
+	return newLockDown(rules.LockDelay, rules.LockResets)
+}
+
+// lockTimer counts the frames for which the mino is on the stack
+type lockTimer struct {
+	Delay int
+	timer int
+}
+
+// Count the frame if the mino is on the stack and return true if the delay has passed
+func (t *lockTimer) tick(grounded bool) bool {
+	if !grounded {
+		return false
+	}
+	t.timer++
+	return t.timer >= t.Delay
+}
+
+// ExtendedPlacement is the lock down of the guideline.
+//   - Each move or rotation after the mino lands resets the delay, up to `Resets` times
+//   - Once a move uses up the resets, the mino locks as soon as it is on the stack
+//   - Falling to a row lower than ever gives the mino the delay and all the resets again
+type ExtendedPlacement struct {
+	lockTimer
+	Resets int
+	moves  int
+	lowest int
+	landed bool
+}
+
+func (*ExtendedPlacement) Name() string {
+	return LOCK_DOWN_EXTENDED
+}
+
+func (l *ExtendedPlacement) Reset() {
+	l.timer, l.moves, l.lowest, l.landed = 0, 0, math.MinInt, false
+}
+
+func (l *ExtendedPlacement) Move() {
+	if l.landed {
+		l.moves++
+	}
+	if l.moves <= l.Resets {
+		l.timer = 0
+	}
+}
+
+func (l *ExtendedPlacement) Fall(y int) {
+	if y > l.lowest {
+		l.timer, l.moves, l.lowest, l.landed = 0, 0, y, false
+	}
+}
+
+func (l *ExtendedPlacement) Update(grounded bool) bool {
+	l.landed = l.landed || grounded
+	return l.tick(grounded) || grounded && l.moves > 0 && l.moves >= l.Resets
+}
+
+// InfinitePlacement resets the delay on every move, rotation and fall, so the mino never locks while it keeps moving
+type InfinitePlacement struct {
+	lockTimer
+}
+
+func (*InfinitePlacement) Name() string {
+	return LOCK_DOWN_INFINITE
+}
+
+func (l *InfinitePlacement) Reset() {
+	l.timer = 0
+}
+
+func (l *InfinitePlacement) Move() {
+	l.timer = 0
+}
+
+func (l *InfinitePlacement) Fall(y int) {
+	l.timer = 0
+}
+
+func (l *InfinitePlacement) Update(grounded bool) bool {
+	return l.tick(grounded)
+}
+
+// StepReset resets the delay only when the mino falls, as in the older games
+type StepReset struct {
+	lockTimer
+}
+
+func (*StepReset) Name() string {
+	return LOCK_DOWN_STEP
+}
+
+func (l *StepReset) Reset() {
+	l.timer = 0
+}
+
+func (*StepReset) Move() {}
+
+func (l *StepReset) Fall(y int) {
+	l.timer = 0
+}
+
+func (l *StepReset) Update(grounded bool) bool {
+	return l.tick(grounded)
+}
+
+// ClassicLockDown never resets the delay, so the frames on the stack add up until the mino locks
+type ClassicLockDown struct {
+	lockTimer
+}
+
+func (*ClassicLockDown) Name() string {
+	return LOCK_DOWN_CLASSIC
+}
+
+func (l *ClassicLockDown) Reset() {
+	l.timer = 0
+}
+
+func (*ClassicLockDown) Move() {}
+
+func (*ClassicLockDown) Fall(y int) {}
+
+func (l *ClassicLockDown) Update(grounded bool) bool {
+	return l.tick(grounded)
+}
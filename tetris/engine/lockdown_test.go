@@ -0,0 +1,81 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestLockDown(t *testing.T) {
+	// Each step of the script is one of
+	//   - 'g': a frame on the stack
+	//   - 'a': a frame in the air
+	//   - 'm': a move or rotation
+	//   - 'f': a fall to the row one lower than the last fall
+	tests := []struct {
+		name   string
+		resets int
+		script string
+		want   int // The index of the step at which the mino locks, or -1 if it does not
+	}{
+		{LOCK_DOWN_EXTENDED, 2, "ggg", 2},
+		{LOCK_DOWN_EXTENDED, 2, "gaagg", 4},
+		{LOCK_DOWN_EXTENDED, 2, "ggmggg", 5},
+		{LOCK_DOWN_EXTENDED, 2, "mmmggg", 5},
+		{LOCK_DOWN_EXTENDED, 2, "ggmggmg", 6},
+		{LOCK_DOWN_EXTENDED, 2, "gmgmafggg", 8},
+		{LOCK_DOWN_EXTENDED, 0, "ggg", 2},
+		{LOCK_DOWN_EXTENDED, 0, "gmg", 2},
+		{LOCK_DOWN_INFINITE, 2, "ggmggmggmgg", -1},
+		{LOCK_DOWN_INFINITE, 2, "ggaggg", 3},
+		{LOCK_DOWN_STEP, 2, "ggmg", 3},
+		{LOCK_DOWN_STEP, 2, "ggafggg", 6},
+		{LOCK_DOWN_CLASSIC, 2, "ggafg", 4},
+		{LOCK_DOWN_CLASSIC, 2, "gaamagg", 6},
+	}
+
+	for _, tt := range tests {
+		l := NewLockDown(Rules{LockDown: tt.name, LockDelay: 3, LockResets: tt.resets})
+		l.Reset()
+		got, y := -1, 0
+		for i, step := range tt.script {
+			locked := false
+			switch step {
+			case 'g', 'a':
+				locked = l.Update(step == 'g')
+			case 'm':
+				l.Move()
+			case 'f':
+				y++
+				l.Fall(y)
+			}
+			if locked {
+				got = i
+				break
+			}
+		}
+		if got != tt.want {
+			t.Errorf("%s %d %q: got the lock at %d, want %d", tt.name, tt.resets, tt.script, got, tt.want)
+		}
+	}
+}
+
+func TestLockDownInEngine(t *testing.T) {
+	rules := DefaultRules()
+	e := NewEngine(0, Endless{}, rules)
+	e.fall(e.Ghost())
+
+	// A mino kept moving on the stack locks once the resets run out
+	frames := 0
+	for ; e.PutPieces == 0 && frames < rules.LockDelay*(rules.LockResets+2); frames++ {
+		input := Input(0)
+		if frames%2 == 0 {
+			input = input.With(ActionRotateRight)
+		}
+		e.Step(input)
+	}
+	if e.PutPieces != 1 {
+		t.Fatalf("got the mino not locked after %d frames", frames)
+	}
+	if frames < rules.LockResets*2 {
+		t.Errorf("got the mino locked after %d frames, want the %d resets used", frames, rules.LockResets)
+	}
+}
@@ -0,0 +1,359 @@
+package engine
+
+import (
+	"fmt"
+	"image/color"
+	"iter"
+	"slices"
+)
+
+var (
+	PURPLE = color.RGBA{106, 50, 165, 255}
+	YELLOW = color.RGBA{255, 213, 0, 255}
+	ORANGE = color.RGBA{255, 121, 28, 255}
+	BLUE   = color.RGBA{6, 119, 186, 255}
+	GREEN  = color.RGBA{114, 203, 59, 255}
+	RED    = color.RGBA{212, 42, 52, 255}
+	CYAN   = color.RGBA{31, 195, 205, 255}
+)
+
+type Angle int
+
+const (
+	Angle0 Angle = iota
+	Angle90
+	Angle180
+	Angle270
+)
+
+type Shape [][]int
+
+type MinoType int
+
+const (
+	MinoTypeI MinoType = iota
+	MinoTypeJ
+	MinoTypeL
+	MinoTypeO
+	MinoTypeS
+	MinoTypeT
+	MinoTypeZ
+)
+
+var minoTypeNames = [...]string{"I", "J", "L", "O", "S", "T", "Z"}
+
+func (t MinoType) String() string {
+	return minoTypeNames[t]
+}
+
+func (t MinoType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *MinoType) UnmarshalText(text []byte) error {
+	i := slices.Index(minoTypeNames[:], string(text))
+	if i < 0 {
+		return fmt.Errorf("unknown mino type: %s", text)
+	}
+	*t = MinoType(i)
+	return nil
+}
+
+// Note: the Mino is fully fixed if IsGrounded is true and BacklashFrame is 0 or ExtendedPlacementCounter is 0
+type BaseMino struct {
+	minoType  MinoType
+	baseShape Shape
+	y         int
+	x         int
+	angle     Angle
+	color     color.Color
+}
+
+func NewBaseMino(minoType MinoType, shape Shape, color color.Color) BaseMino {
+	return BaseMino{
+		minoType:  minoType,
+		baseShape: shape,
+		angle:     Angle0,
+		color:     color,
+	}
+}
+
+func (m BaseMino) Initialize() AbstractMino {
+	m.y, m.x = 0, 4
+	m.angle = Angle0
+	return m
+}
+
+// Shape returns the current shape of the mino
+func (m BaseMino) Shape() Shape {
+	shape := make(Shape, len(m.baseShape))
+	for i := range len(m.baseShape) {
+		shape[i] = make([]int, len(m.baseShape[i]))
+		copy(shape[i], m.baseShape[i])
+	}
+	for range m.angle {
+		shape = Rotate(shape)
+	}
+	return shape
+}
+
+func (m BaseMino) Type() MinoType {
+	return m.minoType
+}
+
+func (m BaseMino) Angle() Angle {
+	return m.angle
+}
+
+func (m BaseMino) Color() color.Color {
+	return m.color
+}
+
+func (m BaseMino) X() int {
+	return m.x
+}
+
+func (m BaseMino) Y() int {
+	return m.y
+}
+
+func (m BaseMino) MoveRight() AbstractMino {
+	m.x++
+	return m
+}
+
+func (m BaseMino) MoveLeft() AbstractMino {
+	m.x--
+	return m
+}
+
+func (m BaseMino) MoveDown() AbstractMino {
+	m.y++
+	return m
+}
+
+func (m BaseMino) MoveUp() AbstractMino {
+	m.y--
+	return m
+}
+
+func (m BaseMino) rotateRight() AbstractMino {
+	m.angle = (m.angle + 1) % 4
+	return m
+}
+
+func (m BaseMino) rotateLeft() AbstractMino {
+	m.angle = (m.angle + 3) % 4
+	return m
+}
+
+type AbstractMino interface {
+	Initialize() AbstractMino
+	MoveRight() AbstractMino
+	MoveLeft() AbstractMino
+	MoveDown() AbstractMino
+	MoveUp() AbstractMino
+	rotateRight() AbstractMino
+	rotateLeft() AbstractMino
+	Kick(turns int, kicks KickTable) iter.Seq2[int, AbstractMino]
+	Shape() Shape
+	Type() MinoType
+	Angle() Angle
+	Color() color.Color
+	X() int
+	Y() int
+}
+
+type HoldingMino struct {
+	AbstractMino
+	Available bool
+}
+
+type MinoI struct {
+	BaseMino
+}
+
+func NewMinoI() MinoI {
+	return MinoI{
+		BaseMino: NewBaseMino(
+			MinoTypeI,
+			[][]int{
+				{0, 0, 0, 0},
+				{1, 1, 1, 1},
+				{0, 0, 0, 0},
+				{0, 0, 0, 0},
+			},
+			CYAN,
+		),
+	}
+}
+
+type MinoJ struct {
+	BaseMino
+}
+
+func NewMinoJ() MinoJ {
+	return MinoJ{
+		BaseMino: NewBaseMino(
+			MinoTypeJ,
+			[][]int{
+				{1, 0, 0},
+				{1, 1, 1},
+				{0, 0, 0},
+			},
+			BLUE,
+		),
+	}
+}
+
+type MinoL struct {
+	BaseMino
+}
+
+func NewMinoL() MinoL {
+	return MinoL{
+		BaseMino: NewBaseMino(
+			MinoTypeL,
+			[][]int{
+				{0, 0, 1},
+				{1, 1, 1},
+				{0, 0, 0},
+			},
+			ORANGE,
+		),
+	}
+}
+
+type MinoO struct {
+	BaseMino
+}
+
+func NewMinoO() MinoO {
+	return MinoO{
+		BaseMino: NewBaseMino(
+			MinoTypeO,
+			[][]int{
+				{1, 1},
+				{1, 1},
+			},
+			YELLOW,
+		),
+	}
+}
+
+type MinoS struct {
+	BaseMino
+}
+
+func NewMinoS() MinoS {
+	return MinoS{
+		BaseMino: NewBaseMino(
+			MinoTypeS,
+			[][]int{
+				{0, 1, 1},
+				{1, 1, 0},
+				{0, 0, 0},
+			},
+			GREEN,
+		),
+	}
+}
+
+type MinoT struct {
+	BaseMino
+}
+
+func NewMinoT() MinoT {
+	return MinoT{
+		BaseMino: NewBaseMino(
+			MinoTypeT,
+			[][]int{
+				{0, 1, 0},
+				{1, 1, 1},
+				{0, 0, 0},
+			},
+			PURPLE,
+		),
+	}
+}
+
+type MinoZ struct {
+	BaseMino
+}
+
+func NewMinoZ() MinoZ {
+	return MinoZ{
+		BaseMino: NewBaseMino(
+			MinoTypeZ,
+			[][]int{
+				{1, 1, 0},
+				{0, 1, 1},
+				{0, 0, 0},
+			},
+			RED,
+		),
+	}
+}
+
+var Minos = []AbstractMino{
+	NewMinoI(),
+	NewMinoJ(),
+	NewMinoL(),
+	NewMinoO(),
+	NewMinoS(),
+	NewMinoT(),
+	NewMinoZ(),
+}
+
+// MinoBag queues the minos generated by its randomizer so that the next ones can be previewed.
+// The same seed and randomizer always generate the same sequence of minos.
+type MinoBag struct {
+	Seed       int64
+	Randomizer Randomizer
+	queue      []AbstractMino
+}
+
+func NewMinoBag(seed int64, randomizer string) MinoBag {
+	return MinoBag{
+		Seed:       seed,
+		Randomizer: NewRandomizer(randomizer, seed),
+	}
+}
+
+func (b *MinoBag) fill(n int) {
+	for len(b.queue) < n {
+		for _, t := range b.Randomizer.Generate() {
+			b.queue = append(b.queue, Minos[t])
+		}
+	}
+}
+
+// Sniff returns the next `n` minos without taking them, generating as many as needed
+func (b *MinoBag) Sniff(n int) []AbstractMino {
+	if n < 0 {
+		panic("n must not be negative")
+	}
+	b.fill(n)
+	preview := make([]AbstractMino, n)
+	copy(preview, b.queue[:n])
+	return preview
+}
+
+func (b *MinoBag) Next() AbstractMino {
+	b.fill(1)
+	mino := b.queue[0].Initialize()
+	b.queue = b.queue[1:]
+	return mino
+}
+
+func Rotate(shape Shape) Shape {
+	n := len(shape)
+	rotated := make([][]int, n)
+	for i := range n {
+		rotated[i] = make([]int, n)
+		for j := range n {
+			rotated[i][j] = shape[n-j-1][i]
+		}
+	}
+	return rotated
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"iter"
+	"testing"
+)
+
+func TestRotate(t *testing.T) {
+	got := Rotate([][]int{
+		{0, 1, 0},
+		{0, 1, 0},
+		{0, 1, 1},
+	})
+	want := [][]int{
+		{0, 0, 0},
+		{1, 1, 1},
+		{1, 0, 0},
+	}
+
+	for y := range len(got) {
+		for x := range len(got[y]) {
+			if got[y][x] != want[y][x] {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+	}
+
+}
+
+func TestRotate180(t *testing.T) {
+	// Return the first kick which fits the board, or -1 if none does
+	fit := func(b Board, minos iter.Seq2[int, AbstractMino]) (int, AbstractMino) {
+		for kick, mino := range minos {
+			if !b.isCollided(mino) {
+				return kick, mino
+			}
+		}
+		return -1, nil
+	}
+
+	t.Run("in place", func(t *testing.T) {
+		for _, name := range Kick180Names {
+			for _, mino := range Minos {
+				for angle := range Angle(4) {
+					m := placeMino(mino, angle, 3, 10)
+					kick, got := fit(NewBoard(), m.Kick(2, Kick180Table(name)))
+					if kick != 0 || got.Angle() != (angle+2)%4 || got.X() != m.X() || got.Y() != m.Y() {
+						t.Errorf("%s: got kick %d for %v at angle %d, want 0 in place", name, kick, mino.Type(), angle)
+					}
+				}
+			}
+		}
+	})
+
+	tests := []struct {
+		name   string
+		kicks  string
+		angle  Angle
+		dx, dy int
+		want   int
+		wantX  int
+		wantY  int
+	}{
+		{"no kick on the floor", KICK_180_NONE, Angle0, 3, 2, -1, 0, 0},
+		{"srs+ up from the floor", KICK_180_SRS_PLUS, Angle0, 3, 2, 1, 0, -1},
+		{"tetrio up from the floor", KICK_180_TETRIO, Angle0, 3, 2, 1, 0, -1},
+		{"no kick on the wall", KICK_180_NONE, Angle90, -1, 10, -1, 0, 0},
+		{"srs+ right from the wall", KICK_180_SRS_PLUS, Angle90, -1, 10, 1, 1, 0},
+		{"tetrio right from the wall", KICK_180_TETRIO, Angle90, -1, 10, 1, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := placeMino(NewMinoT(), tt.angle, tt.dx, tt.dy)
+			kick, got := fit(NewBoard(), m.Kick(2, Kick180Table(tt.kicks)))
+			if kick != tt.want {
+				t.Fatalf("got kick %d, want %d", kick, tt.want)
+			}
+			if got != nil && (got.X()-m.X() != tt.wantX || got.Y()-m.Y() != tt.wantY) {
+				t.Errorf("got offset (%d, %d), want (%d, %d)", got.X()-m.X(), got.Y()-m.Y(), tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
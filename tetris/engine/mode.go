@@ -0,0 +1,193 @@
+package engine
+
+import (
+	"fmt"
+)
+
+const (
+	SPRINT_LINES       = 40
+	SPRINT_SPLIT_LINES = 10
+	ULTRA_FRAMES       = 2 * 60 * TPS
+	MARATHON_LEVELS    = 15
+	SURVIVAL_FRAMES    = 3 * 60 * TPS
+	// Survival sends garbage every interval, which grows by a row every `SURVIVAL_RAMP_FRAMES`
+	SURVIVAL_INTERVAL_FRAMES = 5 * TPS
+	SURVIVAL_RAMP_FRAMES     = 60 * TPS
+	// Garbage rows of the cheese race, which leaves a row free at the bottom of the visible field
+	DEFAULT_CHEESE_HEIGHT    = 10
+	MAX_CHEESE_HEIGHT        = INNER_HEIGHT - 2
+	DEFAULT_CHEESE_MESSINESS = 30
+	// Mixed into the seed of the game so that the holes of the cheese do not follow the minos generated from the same seed
+	CHEESE_SEED_SALT = 0x6a09e667f3bcc908
+)
+
+// Mode decides the goal of a game
+type Mode interface {
+	Name() string
+	// Update is called at the end of every frame and returns true if the game is finished
+	Update(e *Engine) bool
+}
+
+// Starter is implemented by the modes which prepare the board before the first mino spawns
+type Starter interface {
+	Start(e *Engine)
+}
+
+var modeFactories = map[string]func() Mode{
+	"cheese":   func() Mode { return &Cheese{} },
+	"endless":  func() Mode { return Endless{} },
+	"marathon": func() Mode { return NewMarathon() },
+	"sprint":   func() Mode { return NewSprint() },
+	"survival": func() Mode { return NewSurvival() },
+	"ultra":    func() Mode { return NewUltra() },
+}
+
+// Names of the available modes in the order shown to players
+var ModeNames = []string{"endless", "marathon", "sprint", "ultra", "cheese", "survival"}
+
+func NewMode(name string) (Mode, error) {
+	factory, ok := modeFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown mode: %s", name)
+	}
+	return factory(), nil
+}
+
+// Endless never finishes
+type Endless struct{}
+
+func (Endless) Name() string {
+	return "endless"
+}
+
+func (Endless) Update(e *Engine) bool {
+	return false
+}
+
+// Marathon finishes when level `Levels` is completed, which takes 150 lines from level 1 under the fixed goal.
+// The start level and the goal of each level follow the rules, while the other modes always start at level 1 under the fixed goal.
+type Marathon struct {
+	Levels     int
+	StartLevel int    // The level at which the game started
+	Goal       string // The level goal of the game, one of `LevelGoalNames`
+}
+
+func NewMarathon() *Marathon {
+	return &Marathon{Levels: MARATHON_LEVELS}
+}
+
+func (m *Marathon) Name() string {
+	return "marathon"
+}
+
+func (m *Marathon) Start(e *Engine) {
+	e.Level = min(max(e.Rules.StartLevel, 1), m.Levels)
+	if e.Rules.LevelGoal == LEVEL_GOAL_VARIABLE {
+		e.levelGoal = LEVEL_GOAL_VARIABLE
+	}
+	m.StartLevel, m.Goal = e.Level, e.levelGoal
+}
+
+func (m *Marathon) Update(e *Engine) bool {
+	return e.Level > m.Levels
+}
+
+// Sprint finishes when `Goal` lines are cleared.
+// The frame count is recorded every `SPRINT_SPLIT_LINES` lines as split times.
+type Sprint struct {
+	Goal   int
+	Splits []int
+}
+
+func NewSprint() *Sprint {
+	return &Sprint{Goal: SPRINT_LINES}
+}
+
+func (s *Sprint) Name() string {
+	return "sprint"
+}
+
+func (s *Sprint) Update(e *Engine) bool {
+	for len(s.Splits) < min(e.ClearedLines, s.Goal)/SPRINT_SPLIT_LINES {
+		s.Splits = append(s.Splits, e.FrameCount)
+	}
+	return e.ClearedLines >= s.Goal
+}
+
+// Cheese is a race to dig through the garbage rows put at the start, which finishes when no garbage is left.
+// The garbage follows `Rules.CheeseHeight` and `Rules.CheeseMessiness` and is generated from the seed of the game.
+type Cheese struct {
+	Height    int // Garbage rows put at the start
+	Messiness int // Percentage of the garbage rows whose hole moves from the row below
+}
+
+func (c *Cheese) Name() string {
+	return "cheese"
+}
+
+func (c *Cheese) Start(e *Engine) {
+	c.Height = min(max(e.Rules.CheeseHeight, 1), MAX_CHEESE_HEIGHT)
+	c.Messiness = min(max(e.Rules.CheeseMessiness, 0), 100)
+	generator := NewGarbageGenerator(e.MinoBag.Seed^CHEESE_SEED_SALT, float64(c.Messiness)/100)
+	for range c.Height {
+		e.Board.AddGarbage(generator.Next())
+	}
+}
+
+func (c *Cheese) Update(e *Engine) bool {
+	return c.Remaining(e) == 0
+}
+
+// Return the number of garbage rows left
+func (c *Cheese) Remaining(e *Engine) int {
+	return e.Board.CountGarbageRows()
+}
+
+// Ultra finishes when `Frames` frames have passed, and the score at the time is the result
+type Ultra struct {
+	Frames int
+}
+
+func NewUltra() *Ultra {
+	return &Ultra{Frames: ULTRA_FRAMES}
+}
+
+func (u *Ultra) Name() string {
+	return "ultra"
+}
+
+func (u *Ultra) Update(e *Engine) bool {
+	return e.FrameCount >= u.Frames
+}
+
+// Return the number of frames left
+func (u *Ultra) Remaining(e *Engine) int {
+	return max(u.Frames-e.FrameCount, 0)
+}
+
+// Survival receives garbage at regular intervals and finishes when `Frames` frames have passed.
+// The garbage is `Lines` rows at first and a row more every `SURVIVAL_RAMP_FRAMES`, which the attacks can cancel.
+type Survival struct {
+	Frames int
+	Lines  int
+}
+
+func NewSurvival() *Survival {
+	return &Survival{Frames: SURVIVAL_FRAMES, Lines: 1}
+}
+
+func (s *Survival) Name() string {
+	return "survival"
+}
+
+func (s *Survival) Update(e *Engine) bool {
+	if e.FrameCount%SURVIVAL_INTERVAL_FRAMES == 0 {
+		e.ReceiveGarbage(s.Lines + e.FrameCount/SURVIVAL_RAMP_FRAMES)
+	}
+	return e.FrameCount >= s.Frames
+}
+
+// Return the number of frames left
+func (s *Survival) Remaining(e *Engine) int {
+	return max(s.Frames-e.FrameCount, 0)
+}
@@ -0,0 +1,204 @@
+package engine
+
+import (
+	"image/color"
+	"slices"
+	"testing"
+)
+
+func TestSprint(t *testing.T) {
+	sprint := NewSprint()
+	e := NewEngine(0, sprint, DefaultRules())
+
+	e.ClearedLines, e.FrameCount = 25, 100
+	if sprint.Update(e) {
+		t.Errorf("got finished at %d lines, want not finished", e.ClearedLines)
+	}
+	e.ClearedLines, e.FrameCount = 42, 200
+	if !sprint.Update(e) {
+		t.Errorf("got not finished at %d lines, want finished", e.ClearedLines)
+	}
+
+	want := []int{100, 100, 200, 200}
+	if len(sprint.Splits) != len(want) {
+		t.Fatalf("got %v, want %v", sprint.Splits, want)
+	}
+	for i := range want {
+		if sprint.Splits[i] != want[i] {
+			t.Errorf("got %v, want %v", sprint.Splits, want)
+		}
+	}
+}
+
+func TestFinish(t *testing.T) {
+	e := NewEngine(0, NewSprint(), DefaultRules())
+	e.ClearedLines = SPRINT_LINES
+
+	if countEvents(e.Step(Input(0)), EventFinish) != 1 || !e.Finished {
+		t.Fatalf("got not finished, want finished")
+	}
+	frameCount := e.FrameCount
+	if events := e.Step(Input(0).With(ActionHardDrop)); len(events) != 0 || e.FrameCount != frameCount {
+		t.Errorf("got %v, want nothing to happen after finished", events)
+	}
+}
+
+func TestUltra(t *testing.T) {
+	e := NewEngine(0, NewUltra(), DefaultRules())
+	for range ULTRA_FRAMES - 1 {
+		e.Step(Input(0))
+	}
+	if e.Finished {
+		t.Fatalf("got finished at %d frames, want not finished", e.FrameCount)
+	}
+	if countEvents(e.Step(Input(0)), EventFinish) != 1 || e.FrameCount != ULTRA_FRAMES {
+		t.Errorf("got not finished at %d frames, want finished at %d frames", e.FrameCount, ULTRA_FRAMES)
+	}
+}
+
+func TestMarathon(t *testing.T) {
+	rules := DefaultRules()
+	rules.LevelGoal = "unknown"
+	m := NewMarathon()
+	NewEngine(0, m, rules)
+	if m.StartLevel != 1 || m.Goal != LEVEL_GOAL_FIXED {
+		t.Errorf("got level %d and %q goal, want level 1 and %q goal", m.StartLevel, m.Goal, LEVEL_GOAL_FIXED)
+	}
+
+	marathon := NewMarathon()
+	e := NewEngine(0, marathon, DefaultRules())
+
+	for range MARATHON_LEVELS*LINES_PER_LEVEL - 1 {
+		e.levelUp(ClearResult{Lines: 1})
+	}
+	if marathon.Update(e) {
+		t.Fatalf("got finished at level %d, want not finished", e.Level)
+	}
+	e.levelUp(ClearResult{Lines: 1})
+	if !marathon.Update(e) {
+		t.Errorf("got not finished at level %d, want finished after %d lines", e.Level, MARATHON_LEVELS*LINES_PER_LEVEL)
+	}
+}
+
+func TestLevelUp(t *testing.T) {
+	tests := []struct {
+		goal       string
+		startLevel int
+		clears     []ClearResult
+		wantLevel  int
+		wantLines  int
+	}{
+		{LEVEL_GOAL_FIXED, 1, []ClearResult{{Lines: 4}, {Lines: 4}, {Lines: 3}}, 2, 1},
+		{LEVEL_GOAL_FIXED, 5, []ClearResult{{Lines: 4}}, 5, 4},
+		{LEVEL_GOAL_FIXED, MARATHON_LEVELS + 1, nil, MARATHON_LEVELS, 0},
+		{LEVEL_GOAL_FIXED, 0, nil, 1, 0},
+		// A Tetris awards 8 lines, which pass the goal of 5 at level 1 but not that of 10 at level 2
+		{LEVEL_GOAL_VARIABLE, 1, []ClearResult{{Lines: 4}}, 2, 3},
+		{LEVEL_GOAL_VARIABLE, 1, []ClearResult{{Lines: 4}, {Lines: 4, BackToBack: true}}, 3, 5},
+		{LEVEL_GOAL_VARIABLE, 3, []ClearResult{{Lines: 2, Spin: SpinFull}, {Lines: 1}}, 3, 13},
+		{LEVEL_GOAL_VARIABLE, 1, []ClearResult{{Spin: SpinFull}, {Lines: 1}}, 2, 0},
+	}
+
+	for _, tt := range tests {
+		rules := DefaultRules()
+		rules.LevelGoal, rules.StartLevel = tt.goal, tt.startLevel
+		e := NewEngine(0, NewMarathon(), rules)
+		levelUps := 0
+		for _, clear := range tt.clears {
+			e.events = nil
+			e.levelUp(clear)
+			levelUps += countEvents(e.events, EventLevelUp)
+		}
+		if e.Level != tt.wantLevel || e.LevelLines != tt.wantLines {
+			t.Errorf("%s from %d: got level %d with %d lines, want %d with %d", tt.goal, tt.startLevel, e.Level, e.LevelLines, tt.wantLevel, tt.wantLines)
+		}
+		if want := tt.wantLevel - min(max(tt.startLevel, 1), MARATHON_LEVELS); levelUps != want {
+			t.Errorf("%s from %d: got %d level ups, want %d", tt.goal, tt.startLevel, levelUps, want)
+		}
+	}
+}
+
+func TestStartLevelOnlyInMarathon(t *testing.T) {
+	rules := DefaultRules()
+	rules.StartLevel, rules.LevelGoal = 10, LEVEL_GOAL_VARIABLE
+	for _, name := range ModeNames {
+		mode, _ := NewMode(name)
+		e := NewEngine(0, mode, rules)
+		want, wantGoal := 1, LINES_PER_LEVEL
+		if name == "marathon" {
+			want, wantGoal = 10, VARIABLE_GOAL_LINES*10
+		}
+		if e.Level != want || e.LevelGoal() != wantGoal {
+			t.Errorf("%s: got level %d with the goal of %d lines, want %d with %d", name, e.Level, e.LevelGoal(), want, wantGoal)
+		}
+	}
+}
+
+func TestCheese(t *testing.T) {
+	rules := DefaultRules()
+	rules.CheeseHeight = 3
+	cheese := &Cheese{}
+	e := NewEngine(0, cheese, rules)
+
+	if got := cheese.Remaining(e); got != 3 {
+		t.Fatalf("got %d garbage rows, want 3", got)
+	}
+	if got := NewEngine(0, &Cheese{}, rules).Board; got != e.Board {
+		t.Errorf("got different garbage with the same seed")
+	}
+
+	// Fill the holes to clear the garbage rows
+	for y := range OUTER_HEIGHT - SENTINEL_SIZE {
+		for x := SENTINEL_SIZE; x < OUTER_WIDTH-SENTINEL_SIZE; x++ {
+			if e.Board[y][x] == nil && slices.Contains(e.Board[y][:], color.Color(GARBAGE_COLOR)) {
+				e.Board[y][x] = WALL_COLOR
+			}
+		}
+	}
+	e.Board.ClearLines()
+	if !cheese.Update(e) {
+		t.Errorf("got not finished with %d garbage rows, want finished", cheese.Remaining(e))
+	}
+}
+
+func TestSurvival(t *testing.T) {
+	rules := DefaultRules()
+	rules.GarbageDelay = MAX_GARBAGE_DELAY
+	e := NewEngine(0, NewSurvival(), rules)
+	for range SURVIVAL_RAMP_FRAMES {
+		e.Step(Input(0))
+	}
+	// Every interval sends a row, and the last one in the first minute sends 2
+	want := SURVIVAL_RAMP_FRAMES/SURVIVAL_INTERVAL_FRAMES + 1
+	if got := e.IncomingLines(); got != want {
+		t.Errorf("got %d incoming rows, want %d", got, want)
+	}
+}
+
+func TestSurvivalUpdate(t *testing.T) {
+	tests := []struct {
+		frame        int
+		wantLines    int
+		wantFinished bool
+	}{
+		{1, 0, false},
+		{SURVIVAL_INTERVAL_FRAMES - 1, 0, false},
+		{SURVIVAL_INTERVAL_FRAMES, 1, false},
+		{SURVIVAL_INTERVAL_FRAMES + 1, 0, false},
+		{SURVIVAL_RAMP_FRAMES - SURVIVAL_INTERVAL_FRAMES, 1, false},
+		{SURVIVAL_RAMP_FRAMES, 2, false},
+		{SURVIVAL_RAMP_FRAMES + SURVIVAL_INTERVAL_FRAMES, 2, false},
+		{2 * SURVIVAL_RAMP_FRAMES, 3, false},
+		{SURVIVAL_FRAMES - 1, 0, false},
+		{SURVIVAL_FRAMES, 4, true},
+	}
+	for _, tt := range tests {
+		survival := NewSurvival()
+		e := NewEngine(0, survival, DefaultRules())
+		e.FrameCount = tt.frame
+		finished := survival.Update(e)
+		if got := e.IncomingLines(); got != tt.wantLines || finished != tt.wantFinished {
+			t.Errorf("frame %d: got %d rows and finished %v, want %d rows and finished %v", tt.frame, got, finished, tt.wantLines, tt.wantFinished)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package engine
+
+import (
+	"math/rand"
+	"slices"
+)
+
+const (
+	RANDOMIZER_7_BAG  = "7-bag"
+	RANDOMIZER_14_BAG = "14-bag"
+	RANDOMIZER_RANDOM = "random"
+	RANDOMIZER_TGM3   = "tgm3"
+	RANDOMIZER_NES    = "nes"
+)
+
+// Names of the randomizers in the order shown to players
+var RandomizerNames = []string{RANDOMIZER_7_BAG, RANDOMIZER_14_BAG, RANDOMIZER_RANDOM, RANDOMIZER_TGM3, RANDOMIZER_NES}
+
+// Randomizer decides the sequence of minos.
+// It draws only from the source given on construction, so the same seed always generates the same sequence.
+type Randomizer interface {
+	Name() string
+	// Generate returns the minos which come next, at least one
+	Generate() []MinoType
+}
+
+var randomizers = map[string]func(r *rand.Rand) Randomizer{
+	RANDOMIZER_7_BAG:  func(r *rand.Rand) Randomizer { return &BagRandomizer{rand: r, copies: 1} },
+	RANDOMIZER_14_BAG: func(r *rand.Rand) Randomizer { return &BagRandomizer{rand: r, copies: 2} },
+	RANDOMIZER_RANDOM: func(r *rand.Rand) Randomizer { return &PureRandomizer{rand: r} },
+	RANDOMIZER_TGM3:   NewTGM3Randomizer,
+	RANDOMIZER_NES:    func(r *rand.Rand) Randomizer { return &NESRandomizer{rand: r, last: -1} },
+}
+
+// Return the randomizer named `name` seeded by `seed`, or the 7-bag one if there is no such randomizer
+func NewRandomizer(name string, seed int64) Randomizer {
+	newRandomizer, ok := randomizers[name]
+	if !ok {
+		newRandomizer = randomizers[RANDOMIZER_7_BAG]
+	}
+	return newRandomizer(rand.New(rand.NewSource(seed)))
+}
+
+// BagRandomizer shuffles `copies` of each mino into a bag and deals the whole bag before the next one.
+// The 7-bag of the guideline has one copy, and the 14-bag has two.
+type BagRandomizer struct {
+	rand   *rand.Rand
+	copies int
+}
+
+func (b *BagRandomizer) Name() string {
+	if b.copies == 2 {
+		return RANDOMIZER_14_BAG
+	}
+	return RANDOMIZER_7_BAG
+}
+
+func (b *BagRandomizer) Generate() []MinoType {
+	bag := make([]MinoType, 0, len(Minos)*b.copies)
+	for range b.copies {
+		for t := range len(Minos) {
+			bag = append(bag, MinoType(t))
+		}
+	}
+	for i := range len(bag) {
+		j := b.rand.Intn(i + 1)
+		bag[i], bag[j] = bag[j], bag[i]
+	}
+	return bag
+}
+
+// PureRandomizer chooses each mino uniformly at random regardless of the previous ones
+type PureRandomizer struct {
+	rand *rand.Rand
+}
+
+func (*PureRandomizer) Name() string {
+	return RANDOMIZER_RANDOM
+}
+
+func (p *PureRandomizer) Generate() []MinoType {
+	return []MinoType{MinoType(p.rand.Intn(len(Minos)))}
+}
+
+const (
+	TGM3_POOL_COPIES = 5 // Copies of each mino in the pool
+	TGM3_ROLLS       = 6 // Times a mino is drawn at most to avoid the history
+)
+
+// TGM3Randomizer is the randomizer of Tetris The Grand Master 3.
+//   - A mino is drawn from a pool of 35 and redrawn up to `TGM3_ROLLS` times while it is in the history of the last 4 minos
+//   - Each drawn slot of the pool, including the rejected ones, is replaced by the mino which has not come for the longest time
+//   - The history starts with S, Z, S and Z, and the first mino is never S, Z or O
+//   - No mino is known to be in drought until it comes, so the rejected slots are kept until then
+//
+// This follows the reimplementation by colour_thief published on TetrisConcept.
+type TGM3Randomizer struct {
+	rand    *rand.Rand
+	pool    []MinoType
+	history [4]MinoType
+	drought []MinoType // The minos which have come, from the one which has not come for the longest time
+	started bool
+}
+
+func NewTGM3Randomizer(r *rand.Rand) Randomizer {
+	t := &TGM3Randomizer{
+		rand:    r,
+		history: [4]MinoType{MinoTypeS, MinoTypeZ, MinoTypeS, MinoTypeZ},
+	}
+	for mino := range len(Minos) {
+		for range TGM3_POOL_COPIES {
+			t.pool = append(t.pool, MinoType(mino))
+		}
+	}
+	return t
+}
+
+func (*TGM3Randomizer) Name() string {
+	return RANDOMIZER_TGM3
+}
+
+func (t *TGM3Randomizer) Generate() []MinoType {
+	var mino MinoType
+	if !t.started {
+		t.started = true
+		first := []MinoType{MinoTypeI, MinoTypeJ, MinoTypeL, MinoTypeT}
+		mino = first[t.rand.Intn(len(first))]
+	} else {
+		i := 0
+		for roll := range TGM3_ROLLS {
+			i = t.rand.Intn(len(t.pool))
+			mino = t.pool[i]
+			if !slices.Contains(t.history[:], mino) || roll == TGM3_ROLLS-1 {
+				break
+			}
+			if len(t.drought) > 0 {
+				t.pool[i] = t.drought[0]
+			}
+		}
+		t.drought = append(slices.DeleteFunc(t.drought, func(m MinoType) bool { return m == mino }), mino)
+		t.pool[i] = t.drought[0]
+	}
+	copy(t.history[:], t.history[1:])
+	t.history[len(t.history)-1] = mino
+	return []MinoType{mino}
+}
+
+// NESRandomizer is the randomizer of the NES Tetris.
+// It rolls a die of 8 faces, and rolls once more among the 7 minos if the die shows the extra face or the previous mino.
+type NESRandomizer struct {
+	rand *rand.Rand
+	last int // The previous mino, or -1 before the first one
+}
+
+func (*NESRandomizer) Name() string {
+	return RANDOMIZER_NES
+}
+
+func (n *NESRandomizer) Generate() []MinoType {
+	mino := n.rand.Intn(len(Minos) + 1)
+	if mino == len(Minos) || mino == n.last {
+		mino = n.rand.Intn(len(Minos))
+	}
+	n.last = mino
+	return []MinoType{MinoType(mino)}
+}
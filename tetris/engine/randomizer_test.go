@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+// Return the first n minos generated by the randomizer
+func generate(r Randomizer, n int) []MinoType {
+	var minos []MinoType
+	for len(minos) < n {
+		minos = append(minos, r.Generate()...)
+	}
+	return minos[:n]
+}
+
+func TestRandomizerSeed(t *testing.T) {
+	for _, name := range RandomizerNames {
+		a, b := generate(NewRandomizer(name, 42), 100), generate(NewRandomizer(name, 42), 100)
+		if !slices.Equal(a, b) {
+			t.Errorf("%s: got different sequences with the same seed", name)
+		}
+		if got := NewRandomizer(name, 42).Name(); got != name {
+			t.Errorf("got %q, want %q", got, name)
+		}
+	}
+}
+
+func TestBagRandomizer(t *testing.T) {
+	tests := []struct {
+		name   string
+		copies int
+	}{
+		{RANDOMIZER_7_BAG, 1},
+		{RANDOMIZER_14_BAG, 2},
+	}
+
+	for _, tt := range tests {
+		size := len(Minos) * tt.copies
+		minos := generate(NewRandomizer(tt.name, 0), size*10)
+		for bag := range slices.Chunk(minos, size) {
+			for mino := range len(Minos) {
+				if got := countMino(bag, MinoType(mino)); got != tt.copies {
+					t.Fatalf("%s: got %d %v in a bag, want %d", tt.name, got, MinoType(mino), tt.copies)
+				}
+			}
+		}
+	}
+}
+
+func countMino(minos []MinoType, mino MinoType) int {
+	n := 0
+	for _, m := range minos {
+		if m == mino {
+			n++
+		}
+	}
+	return n
+}
+
+func TestTGM3Randomizer(t *testing.T) {
+	for seed := range int64(100) {
+		first := generate(NewRandomizer(RANDOMIZER_TGM3, seed), 1)[0]
+		if first == MinoTypeS || first == MinoTypeZ || first == MinoTypeO {
+			t.Fatalf("seed %d: got %v as the first mino", seed, first)
+		}
+	}
+
+	// The first mino is not counted in the drought order, which starts empty
+	r := NewRandomizer(RANDOMIZER_TGM3, 0).(*TGM3Randomizer)
+	generate(r, 1)
+	if len(r.drought) != 0 {
+		t.Errorf("got %v in the drought order after the first mino, want none", r.drought)
+	}
+
+	// The history makes a mino much less likely to come twice in a row than the 1/7 by chance
+	minos := generate(NewRandomizer(RANDOMIZER_TGM3, 0), 7000)
+	repeats := 0
+	for i := 1; i < len(minos); i++ {
+		if minos[i] == minos[i-1] {
+			repeats++
+		}
+	}
+	if repeats > len(minos)/50 {
+		t.Errorf("got %d repeats in %d minos, want at most %d", repeats, len(minos), len(minos)/50)
+	}
+}
+
+func TestNESRandomizer(t *testing.T) {
+	// A repeat needs the first roll to show the extra face or the previous mino and the reroll to show it again,
+	// so it happens with the probability 2/8 * 1/7 instead of 1/7
+	minos := generate(NewRandomizer(RANDOMIZER_NES, 0), 7000)
+	repeats := 0
+	for i := 1; i < len(minos); i++ {
+		if minos[i] == minos[i-1] {
+			repeats++
+		}
+	}
+	if repeats == 0 || repeats > len(minos)/14 {
+		t.Errorf("got %d repeats in %d minos, want between 1 and %d", repeats, len(minos), len(minos)/14)
+	}
+}
+
+func TestMinoBag(t *testing.T) {
+	b := NewMinoBag(0, RANDOMIZER_7_BAG)
+	if got := b.Sniff(0); len(got) != 0 {
+		t.Errorf("got %d minos, want 0", len(got))
+	}
+	preview := b.Sniff(20)
+	if len(preview) != 20 {
+		t.Fatalf("got %d minos, want 20", len(preview))
+	}
+	for i, want := range preview {
+		if got := b.Next(); got.Type() != want.Type() {
+			t.Fatalf("got %v at %d, want %v", got.Type(), i, want.Type())
+		}
+	}
+}
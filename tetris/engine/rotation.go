@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"iter"
+	"slices"
+)
+
+const (
+	ROTATION_SRS = "srs"
+	ROTATION_ARS = "ars"
+	ROTATION_NES = "nes"
+)
+
+// Names of the rotation systems in the order shown to players
+var RotationSystemNames = []string{ROTATION_SRS, ROTATION_ARS, ROTATION_NES}
+
+// RotationSystem decides the orientation and position in which minos spawn and how they rotate
+type RotationSystem interface {
+	Name() string
+	// Spawn returns the mino at its spawn position and orientation
+	Spawn(mino AbstractMino) AbstractMino
+	// Rotate yields the candidates of the rotation in the order to be tried with the index of the kick.
+	// The board is given for the systems whose kicks depend on the blocks around the mino.
+	Rotate(b *Board, mino AbstractMino, clockwise bool) iter.Seq2[int, AbstractMino]
+}
+
+var rotationSystems = map[string]RotationSystem{
+	ROTATION_SRS: &SRS{Kicks: &SRS_KICK_TABLES},
+	ROTATION_ARS: ARS,
+	ROTATION_NES: NES,
+}
+
+// Return the rotation system named `name`, or SRS if there is no such system
+func RotationSystemByName(name string) RotationSystem {
+	if system, ok := rotationSystems[name]; ok {
+		return system
+	}
+	return rotationSystems[ROTATION_SRS]
+}
+
+// Return the rotation system of the rules, with the custom kick tables if SRS is chosen and they are given
+func NewRotationSystem(rules Rules) RotationSystem {
+	system := RotationSystemByName(rules.RotationSystem)
+	if _, ok := system.(*SRS); ok && rules.Kicks != nil {
+		return &SRS{Kicks: rules.Kicks}
+	}
+	return system
+}
+
+// SRS is the Super Rotation System of the guideline.
+// Minos spawn flat side down and are kicked by `Kicks`, which are `SRS_KICK_TABLES` unless customized.
+type SRS struct {
+	Kicks *KickTables
+}
+
+func (*SRS) Name() string {
+	return ROTATION_SRS
+}
+
+func (*SRS) Spawn(mino AbstractMino) AbstractMino {
+	return mino.Initialize()
+}
+
+func (r *SRS) Rotate(b *Board, mino AbstractMino, clockwise bool) iter.Seq2[int, AbstractMino] {
+	tables, turns := r.Kicks.Right, 1
+	if !clockwise {
+		tables, turns = r.Kicks.Left, 3
+	}
+	kicks, ok := tables[mino.Type()]
+	if !ok {
+		kicks = noKicks
+	}
+	return mino.Kick(turns, kicks)
+}
+
+// A state of a mino in a rotation system: the orientation of the mino and its offset from the SRS position
+type rotationState struct {
+	angle  Angle
+	offset Offset
+}
+
+// StateRotation is a rotation system whose states are defined relative to the SRS orientations.
+//   - `States` lists the 4 states of each mino in clockwise order, starting from the spawn state
+//   - The minos with 2 states repeat them, so that they switch between them in both directions
+//   - If `WallKick` is set, a blocked rotation is retried one cell right and then one cell left,
+//     except for I and for J, L and T which are blocked in the center column (see `isCenterColumnBlocked`)
+type StateRotation struct {
+	name     string
+	States   map[MinoType][4]rotationState
+	WallKick bool
+}
+
+func (r *StateRotation) Name() string {
+	return r.name
+}
+
+func (r *StateRotation) Spawn(mino AbstractMino) AbstractMino {
+	return turn(mino.Initialize(), rotationState{}, r.States[mino.Type()][0])
+}
+
+func (r *StateRotation) Rotate(b *Board, mino AbstractMino, clockwise bool) iter.Seq2[int, AbstractMino] {
+	states := r.States[mino.Type()]
+	i := max(slices.IndexFunc(states[:], func(s rotationState) bool { return s.angle == mino.Angle() }), 0)
+	j := (i + 1) % 4
+	if !clockwise {
+		j = (i + 3) % 4
+	}
+	rotated := turn(mino, states[i], states[j])
+
+	return func(yield func(int, AbstractMino) bool) {
+		if !yield(0, rotated) || !r.WallKick || !canWallKick(b, rotated) {
+			return
+		}
+		_ = yield(1, rotated.MoveRight()) && yield(2, rotated.MoveLeft())
+	}
+}
+
+// Turn the mino from a state to another
+func turn(mino AbstractMino, from, to rotationState) AbstractMino {
+	for range (to.angle - from.angle + 4) % 4 {
+		mino = mino.rotateRight()
+	}
+	return translate(mino, Offset{to.offset.X - from.offset.X, to.offset.Y - from.offset.Y})
+}
+
+func canWallKick(b *Board, rotated AbstractMino) bool {
+	switch rotated.Type() {
+	case MinoTypeI:
+		return false
+	case MinoTypeJ, MinoTypeL, MinoTypeT:
+		return !isCenterColumnBlocked(b, rotated)
+	}
+	return true
+}
+
+// The center column rule of ARS.
+// Return true if the first cell of the rotated mino which overlaps the board in reading order is in the center column.
+func isCenterColumnBlocked(b *Board, rotated AbstractMino) bool {
+	for dy, row := range rotated.Shape() {
+		for dx, cell := range row {
+			if cell != 0 && b.IsOccupied(rotated.X()+dx, rotated.Y()+dy) {
+				return dx == 1
+			}
+		}
+	}
+	return false
+}
+
+// The states of J, L and T which spawn flat side up
+var flatSideUpStates = [4]rotationState{{Angle180, Offset{0, 0}}, {Angle270, Offset{0, 0}}, {Angle0, Offset{0, 0}}, {Angle90, Offset{0, 0}}}
+
+// ARS is the Arika Rotation System of the TGM series.
+// Minos are aligned to the bottom of their boxes, and J, L and T spawn flat side up.
+var ARS = &StateRotation{
+	name: ROTATION_ARS,
+	States: map[MinoType][4]rotationState{
+		MinoTypeI: {{Angle0, Offset{0, 0}}, {Angle90, Offset{0, 0}}, {Angle0, Offset{0, 0}}, {Angle90, Offset{0, 0}}},
+		MinoTypeJ: {{Angle180, Offset{0, 0}}, {Angle270, Offset{0, 0}}, {Angle0, Offset{0, -1}}, {Angle90, Offset{0, 0}}},
+		MinoTypeL: {{Angle180, Offset{0, 0}}, {Angle270, Offset{0, 0}}, {Angle0, Offset{0, -1}}, {Angle90, Offset{0, 0}}},
+		MinoTypeO: {},
+		MinoTypeS: {{Angle0, Offset{0, -1}}, {Angle270, Offset{0, 0}}, {Angle0, Offset{0, -1}}, {Angle270, Offset{0, 0}}},
+		MinoTypeT: {{Angle180, Offset{0, 0}}, {Angle270, Offset{0, 0}}, {Angle0, Offset{0, -1}}, {Angle90, Offset{0, 0}}},
+		MinoTypeZ: {{Angle0, Offset{0, -1}}, {Angle90, Offset{0, 0}}, {Angle0, Offset{0, -1}}, {Angle90, Offset{0, 0}}},
+	},
+	WallKick: true,
+}
+
+// NES is the rotation system of the classic NES Tetris.
+// Minos rotate around the center of their boxes without any kicks, and J, L and T spawn flat side up.
+var NES = &StateRotation{
+	name: ROTATION_NES,
+	States: map[MinoType][4]rotationState{
+		MinoTypeI: {{Angle180, Offset{0, 0}}, {Angle90, Offset{0, 0}}, {Angle180, Offset{0, 0}}, {Angle90, Offset{0, 0}}},
+		MinoTypeJ: flatSideUpStates,
+		MinoTypeL: flatSideUpStates,
+		MinoTypeO: {},
+		MinoTypeS: {{Angle180, Offset{0, 0}}, {Angle90, Offset{0, 0}}, {Angle180, Offset{0, 0}}, {Angle90, Offset{0, 0}}},
+		MinoTypeT: flatSideUpStates,
+		MinoTypeZ: {{Angle180, Offset{0, 0}}, {Angle90, Offset{0, 0}}, {Angle180, Offset{0, 0}}, {Angle90, Offset{0, 0}}},
+	},
+}
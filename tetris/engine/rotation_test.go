@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"testing"
+)
+
+// Return the first candidate of the rotation which fits the board with the index of its kick, or -1 if none does
+func firstFit(b *Board, system RotationSystem, mino AbstractMino, clockwise bool) (int, AbstractMino) {
+	for kick, rotated := range system.Rotate(b, mino, clockwise) {
+		if !b.isCollided(rotated) {
+			return kick, rotated
+		}
+	}
+	return -1, nil
+}
+
+func TestSpawn(t *testing.T) {
+	tests := []struct {
+		system string
+		mino   AbstractMino
+		want   Angle
+	}{
+		{ROTATION_SRS, NewMinoT(), Angle0},
+		{ROTATION_SRS, NewMinoI(), Angle0},
+		{ROTATION_ARS, NewMinoT(), Angle180},
+		{ROTATION_ARS, NewMinoI(), Angle0},
+		{ROTATION_NES, NewMinoL(), Angle180},
+		{ROTATION_NES, NewMinoI(), Angle180},
+	}
+
+	for _, tt := range tests {
+		e := NewEngine(0, Endless{}, Rules{RotationSystem: tt.system})
+		got := e.RotationSystem.Spawn(tt.mino)
+		if got.Angle() != tt.want || got.X() != 4 || e.Board.isCollided(got) {
+			t.Errorf("%s: got %v at angle %d and x = %d, want angle %d and x = 4", tt.system, tt.mino.Type(), got.Angle(), got.X(), tt.want)
+		}
+	}
+}
+
+func TestRotationCycle(t *testing.T) {
+	// Rotating 4 times in either direction returns the mino to where it was
+	b := NewBoard()
+	for _, name := range RotationSystemNames {
+		system := RotationSystemByName(name)
+		for _, clockwise := range []bool{true, false} {
+			for _, mino := range Minos {
+				spawned := translate(system.Spawn(mino), Offset{0, -10})
+				got := spawned
+				for range 4 {
+					var kick int
+					if kick, got = firstFit(&b, system, got, clockwise); kick != 0 {
+						t.Fatalf("%s: got kick %d for %v on the empty board, want 0", name, kick, mino.Type())
+					}
+				}
+				if got.Angle() != spawned.Angle() || got.X() != spawned.X() || got.Y() != spawned.Y() {
+					t.Errorf("%s: got %v at (%d, %d, %d), want (%d, %d, %d)", name, mino.Type(),
+						got.X(), got.Y(), got.Angle(), spawned.X(), spawned.Y(), spawned.Angle())
+				}
+			}
+		}
+	}
+}
+
+func TestWallKick(t *testing.T) {
+	// T spawned by ARS is placed in the middle of the board and points left after a clockwise rotation
+	tests := []struct {
+		name      string
+		system    RotationSystem
+		clockwise bool
+		blocked   [][2]int // Cells relative to the box of the mino
+		want      int
+	}{
+		{"ARS in place", ARS, true, nil, 0},
+		{"ARS kicks right", ARS, true, [][2]int{{0, 1}}, 1},
+		{"ARS center column rule", ARS, true, [][2]int{{1, 0}}, -1},
+		{"ARS center column rule after the side", ARS, true, [][2]int{{1, 0}, {0, 1}}, -1},
+		{"ARS kicks left", ARS, false, [][2]int{{2, 1}}, 2},
+		{"NES never kicks", NES, true, [][2]int{{0, 1}}, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mino := placeMino(NewMinoT(), Angle180, 3, 10)
+			b := NewBoard()
+			for _, cell := range tt.blocked {
+				b[mino.Y()+cell[1]][mino.X()+cell[0]] = WALL_COLOR
+			}
+			if kick, _ := firstFit(&b, tt.system, mino, tt.clockwise); kick != tt.want {
+				t.Errorf("got kick %d, want %d", kick, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package engine
+
+// Rules are the options of the game mechanics chosen by the player.
+// Unlike `Handling`, they change the game itself, so they are recorded in replays as well.
+type Rules struct {
+	RotationSystem  string `json:"rotation_system"`  // One of `RotationSystemNames`
+	Kick180         string `json:"kick_180"`         // One of `Kick180Names`
+	Randomizer      string `json:"randomizer"`       // One of `RandomizerNames`
+	IRS             bool   `json:"irs"`              // Rotate a new mino by the rotation keys held when it spawns
+	IHS             bool   `json:"ihs"`              // Hold a new mino if the hold key is held when it spawns
+	AllSpin         bool   `json:"all_spin"`         // Award the spins of the minos other than T as well
+	ARE             int    `json:"are"`              // Frames from the lock of a mino to the spawn of the next one
+	LineClearDelay  int    `json:"line_clear_delay"` // Frames for which the cleared lines are left empty before ARE
+	Gravity         string `json:"gravity"`          // One of `GravityNames`
+	LockDown        string `json:"lock_down"`        // One of `LockDownNames`
+	LockDelay       int    `json:"lock_delay"`       // Frames for which a mino stays on the stack before it locks
+	LockResets      int    `json:"lock_resets"`      // Moves and rotations which reset the lock delay under the extended placement
+	StartLevel      int    `json:"start_level"`      // The level at which the marathon starts
+	LevelGoal       string `json:"level_goal"`       // One of `LevelGoalNames` for the marathon
+	CheeseHeight    int    `json:"cheese_height"`    // Garbage rows at the start of the cheese race
+	CheeseMessiness int    `json:"cheese_messiness"` // Percentage of the garbage rows whose hole moves from the row below
+	GarbageDelay    int    `json:"garbage_delay"`    // Minos to lock before the received garbage is inserted
+	// The attack table which replaces `DEFAULT_ATTACK_TABLE`, or nil to use it
+	Attack *AttackTable `json:"attack,omitempty"`
+	// Kick tables which replace those of SRS, or nil to use `SRS_KICK_TABLES`.
+	// They are kept in the rules so that replays can be played back without the file they were loaded from.
+	Kicks *KickTables `json:"kicks,omitempty"`
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		RotationSystem:  ROTATION_SRS,
+		Kick180:         KICK_180_SRS_PLUS,
+		Randomizer:      RANDOMIZER_7_BAG,
+		IRS:             true,
+		IHS:             true,
+		Gravity:         GRAVITY_GUIDELINE,
+		LockDown:        LOCK_DOWN_EXTENDED,
+		LockDelay:       DEFAULT_LOCK_DELAY,
+		LockResets:      DEFAULT_LOCK_RESETS,
+		StartLevel:      1,
+		LevelGoal:       LEVEL_GOAL_FIXED,
+		CheeseHeight:    DEFAULT_CHEESE_HEIGHT,
+		CheeseMessiness: DEFAULT_CHEESE_MESSINESS,
+	}
+}
@@ -0,0 +1,149 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Base points of line clears indexed by the number of lines, following the guideline
+var (
+	CLEAR_POINTS      = [...]int{0, 100, 300, 500, 800}
+	MINI_TSPIN_POINTS = [...]int{100, 200, 400}
+	TSPIN_POINTS      = [...]int{400, 800, 1200, 1600}
+	PERFECT_POINTS    = [...]int{0, 800, 1200, 1800, 2000}
+)
+
+const (
+	BACK_TO_BACK_PERFECT_TETRIS_POINTS = 3200
+	COMBO_POINTS                       = 50
+	SOFT_DROP_POINTS                   = 1
+	HARD_DROP_POINTS                   = 2
+)
+
+// ClearResult describes what a locked mino achieved
+type ClearResult struct {
+	Mino         MinoType
+	Lines        int
+	Spin         SpinType
+	BackToBack   bool
+	Combo        int
+	PerfectClear bool
+	Points       int
+}
+
+// Return the points of the clear before the back-to-back, combo, perfect clear and level bonuses
+func basePoints(lines int, spin SpinType) int {
+	switch spin {
+	case SpinMini:
+		return MINI_TSPIN_POINTS[min(lines, len(MINI_TSPIN_POINTS)-1)]
+	case SpinFull:
+		return TSPIN_POINTS[lines]
+	}
+	return CLEAR_POINTS[lines]
+}
+
+// AwardedLines returns the lines counted toward the variable goal, which are the base points
+// including the back-to-back bonus divided by 100, such as 8 for a Tetris and 12 for a T-Spin Double
+func (r ClearResult) AwardedLines() int {
+	points := basePoints(r.Lines, r.Spin)
+	if r.BackToBack {
+		points = points * 3 / 2
+	}
+	return points / 100
+}
+
+// Return true if the clear keeps the back-to-back chain
+func (r ClearResult) IsDifficult() bool {
+	return r.Lines == 4 || r.Lines > 0 && r.Spin != SpinNone
+}
+
+// Labels returns the names of the achievements such as "T-Spin" or "Double"
+func (r ClearResult) Labels() []string {
+	names := []string{"", "Single", "Double", "Triple", "Tetris"}
+	labels := []string{}
+	if r.BackToBack {
+		labels = append(labels, "B2B")
+	}
+	switch r.Spin {
+	case SpinMini:
+		labels = append(labels, fmt.Sprintf("%v-Spin Mini", r.Mino))
+	case SpinFull:
+		labels = append(labels, fmt.Sprintf("%v-Spin", r.Mino))
+	}
+	if r.Lines > 0 {
+		labels = append(labels, names[r.Lines])
+	}
+	if r.Combo > 0 {
+		labels = append(labels, fmt.Sprintf("%d Combo", r.Combo))
+	}
+	if r.PerfectClear {
+		labels = append(labels, "Perfect Clear")
+	}
+	return labels
+}
+
+func (r ClearResult) String() string {
+	return strings.Join(r.Labels(), " ")
+}
+
+// Score accumulates points according to the guideline.
+//   - Spins of any mino are awarded as T-spins
+//   - Line clears, T-spins, combos and perfect clears are multiplied by the level
+//   - Difficult clears (Tetris and T-spins with lines) in a row get 1.5 times as back-to-back
+//   - Soft drop gets 1 point and hard drop gets 2 points per cell, which are multiplied by the level as well
+type Score struct {
+	Points     int
+	Combo      int // -1 while no combo continues
+	BackToBack bool
+	LastClear  ClearResult
+}
+
+func NewScore() Score {
+	return Score{Combo: -1}
+}
+
+// Clear awards the points for a locked mino and returns the result
+func (s *Score) Clear(mino MinoType, lines int, spin SpinType, perfectClear bool, level int) ClearResult {
+	result := ClearResult{Mino: mino, Lines: lines, Spin: spin, PerfectClear: perfectClear}
+
+	points := basePoints(lines, spin)
+	if lines == 0 {
+		s.Combo = -1
+	} else {
+		s.Combo++
+		result.Combo = s.Combo
+		result.BackToBack = s.BackToBack && result.IsDifficult()
+		s.BackToBack = result.IsDifficult()
+	}
+	if result.BackToBack {
+		points = points * 3 / 2
+	}
+
+	if result.Combo > 0 {
+		points += COMBO_POINTS * result.Combo
+	}
+
+	if perfectClear {
+		if result.BackToBack && lines == 4 {
+			points += BACK_TO_BACK_PERFECT_TETRIS_POINTS
+		} else {
+			points += PERFECT_POINTS[lines]
+		}
+	}
+
+	result.Points = points * level
+	s.Points += result.Points
+	if lines > 0 || spin != SpinNone {
+		s.LastClear = result
+	}
+	return result
+}
+
+// Drop awards the points for the cells the mino fell by soft or hard drop
+func (s *Score) Drop(cells int, hard bool, level int) {
+	if hard {
+		s.Points += HARD_DROP_POINTS * cells * level
+	} else {
+		s.Points += SOFT_DROP_POINTS * cells * level
+	}
+}
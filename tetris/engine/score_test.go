@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	type clear struct {
+		lines   int
+		spin    SpinType
+		perfect bool
+	}
+	tests := []struct {
+		name   string
+		clears []clear
+		want   int
+	}{
+		{"single", []clear{{1, SpinNone, false}}, 100},
+		{"tetris", []clear{{4, SpinNone, false}}, 800},
+		{"t-spin mini", []clear{{0, SpinMini, false}}, 100},
+		{"t-spin double", []clear{{2, SpinFull, false}}, 1200},
+		{"back-to-back tetris", []clear{{4, SpinNone, false}, {4, SpinNone, false}}, 800 + (1200 + 50)},
+		{"back-to-back is broken by single", []clear{{4, SpinNone, false}, {1, SpinNone, false}, {4, SpinNone, false}}, 800 + (100 + 50) + (800 + 100)},
+		{"back-to-back is kept by t-spin without lines", []clear{{4, SpinNone, false}, {0, SpinFull, false}, {4, SpinNone, false}}, 800 + 400 + 1200},
+		{"combo", []clear{{1, SpinNone, false}, {1, SpinNone, false}, {1, SpinNone, false}, {0, SpinNone, false}, {1, SpinNone, false}}, 100 + 150 + 200 + 0 + 100},
+		{"perfect clear", []clear{{2, SpinNone, false}, {0, SpinNone, false}, {4, SpinNone, true}}, 300 + 0 + (800 + 2000)},
+		{"back-to-back perfect tetris", []clear{{4, SpinNone, false}, {0, SpinNone, false}, {4, SpinNone, true}}, 800 + 0 + (1200 + 3200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScore()
+			for _, c := range tt.clears {
+				s.Clear(MinoTypeT, c.lines, c.spin, c.perfect, 1)
+			}
+			if s.Points != tt.want {
+				t.Errorf("got %d, want %d", s.Points, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreLevel(t *testing.T) {
+	s := NewScore()
+	s.Clear(MinoTypeI, 4, SpinNone, false, 3)
+	s.Drop(10, true, 3)
+	s.Drop(5, false, 3)
+	if want := (800 + 20 + 5) * 3; s.Points != want {
+		t.Errorf("got %d, want %d", s.Points, want)
+	}
+}
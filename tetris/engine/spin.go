@@ -0,0 +1,77 @@
+package engine
+
+type SpinType int
+
+const (
+	SpinNone SpinType = iota
+	SpinMini
+	SpinFull
+)
+
+const (
+	// Index of the kick which always makes a T-spin full, e.g. the last kick of a T-spin triple
+	TSPIN_FULL_KICK = 4
+
+	// Kick index passed to `Spin` when the last movement is not a rotation
+	NO_ROTATION = -1
+
+	// Kick index passed to `Spin` when the last movement is a 180 rotation, whose kicks never make a T-spin full
+	ROTATION_180 = -2
+)
+
+// Spin classifies how the mino is locked.
+//   - `kick` is the index of the kick used by the last rotation, or `NO_ROTATION` if the last movement is not a rotation
+//   - T is judged by `TSpin`
+//   - The other minos except O are mini spins if they cannot move left, right nor up (so-called all-spin)
+func (b *Board) Spin(mino AbstractMino, kick int) SpinType {
+	if kick == NO_ROTATION {
+		return SpinNone
+	}
+	switch mino.Type() {
+	case MinoTypeT:
+		return b.TSpin(mino, kick)
+	case MinoTypeO:
+		return SpinNone
+	}
+	if b.isCollided(mino.MoveLeft()) && b.isCollided(mino.MoveRight()) && b.isCollided(mino.MoveUp()) {
+		return SpinMini
+	}
+	return SpinNone
+}
+
+// TSpin judges the T-spin by the 3-corner rule.
+//   - The mino must be T and its last movement must be a rotation, which the caller is responsible for
+//   - If 3 or more corners around the center of T are occupied, it is a T-spin
+//   - It is a full T-spin if both corners on the pointing side are occupied or the rotation used `TSPIN_FULL_KICK`,
+//     otherwise a mini T-spin
+func (b *Board) TSpin(mino AbstractMino, kick int) SpinType {
+	if mino.Type() != MinoTypeT {
+		return SpinNone
+	}
+
+	// Corners of the 3x3 box in clockwise order from the top-left
+	x, y := mino.X(), mino.Y()
+	corners := [4]bool{
+		b.IsOccupied(x, y),
+		b.IsOccupied(x+2, y),
+		b.IsOccupied(x+2, y+2),
+		b.IsOccupied(x, y+2),
+	}
+
+	occupied := 0
+	for _, c := range corners {
+		if c {
+			occupied++
+		}
+	}
+	if occupied < 3 {
+		return SpinNone
+	}
+
+	// T points up at Angle0, and the pointing side turns clockwise with the angle
+	front := int(mino.Angle())
+	if corners[front] && corners[(front+1)%4] || kick == TSPIN_FULL_KICK {
+		return SpinFull
+	}
+	return SpinMini
+}
@@ -0,0 +1,172 @@
+package engine
+
+import (
+	"testing"
+)
+
+// Build a board whose bottom rows are given by `rows`, where '#' is a filled cell
+func newBoardFromRows(rows ...string) Board {
+	board := NewBoard()
+	bottom := OUTER_HEIGHT - SENTINEL_SIZE - len(rows)
+	for dy, row := range rows {
+		for dx, c := range row {
+			if c == '#' {
+				board[bottom+dy][SENTINEL_SIZE+dx] = WALL_COLOR
+			}
+		}
+	}
+	return board
+}
+
+// Place the mino so that its bounding box starts at the `dx`-th column and the `dy`-th row from the bottom
+func placeMino(mino AbstractMino, angle Angle, dx, dy int) AbstractMino {
+	mino = mino.Initialize()
+	for range angle {
+		mino = mino.rotateRight()
+	}
+	for ; mino.X() > SENTINEL_SIZE+dx; mino = mino.MoveLeft() {
+	}
+	for ; mino.X() < SENTINEL_SIZE+dx; mino = mino.MoveRight() {
+	}
+	for ; mino.Y() < OUTER_HEIGHT-SENTINEL_SIZE-dy; mino = mino.MoveDown() {
+	}
+	return mino
+}
+
+func TestSpin(t *testing.T) {
+	tests := []struct {
+		name  string
+		rows  []string
+		mino  AbstractMino
+		angle Angle
+		x, y  int
+		kick  int
+		want  SpinType
+	}{
+		{
+			"t-spin double",
+			[]string{
+				"##........",
+				"#...######",
+				"##.#######",
+			},
+			NewMinoT(), Angle180, 1, 3, 0,
+			SpinFull,
+		},
+		{
+			"t-spin mini",
+			[]string{
+				"#.........",
+				"...#######",
+				"#.########",
+			},
+			NewMinoT(), Angle0, 0, 3, 0,
+			SpinMini,
+		},
+		{
+			"t-spin mini is upgraded by the last kick",
+			[]string{
+				"#.........",
+				"...#######",
+				"#.########",
+			},
+			NewMinoT(), Angle0, 0, 3, TSPIN_FULL_KICK,
+			SpinFull,
+		},
+		{
+			"no t-spin with 2 corners",
+			[]string{
+				"#...######",
+				"##.#######",
+			},
+			NewMinoT(), Angle180, 1, 3, 0,
+			SpinNone,
+		},
+		{
+			"no t-spin without rotation",
+			[]string{
+				"##........",
+				"#...######",
+				"##.#######",
+			},
+			NewMinoT(), Angle180, 1, 3, NO_ROTATION,
+			SpinNone,
+		},
+		{
+			"s-spin",
+			[]string{
+				"###.......",
+				"#..#######",
+				"..########",
+			},
+			NewMinoS(), Angle0, 0, 2, 0,
+			SpinMini,
+		},
+		{
+			"no s-spin if movable",
+			[]string{
+				"...#######",
+				"..########",
+			},
+			NewMinoS(), Angle0, 0, 2, 0,
+			SpinNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			board := newBoardFromRows(tt.rows...)
+			mino := placeMino(tt.mino, tt.angle, tt.x, tt.y)
+			if board.isCollided(mino) {
+				t.Fatalf("the mino is collided with the board")
+			}
+			if got := board.Spin(mino, tt.kick); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Drive the engine to lock a T mino at the given position after the rotation
+func lockTSpin(t *testing.T, rows []string, angle Angle, x, y int, rotation Action) (result ClearResult, kick int) {
+	t.Helper()
+	e := NewEngine(0, Endless{}, DefaultRules())
+	e.Board = newBoardFromRows(rows...)
+	e.CurrentMino = placeMino(NewMinoT(), angle, x, y)
+	if e.Board.isCollided(e.CurrentMino) {
+		t.Fatalf("the mino is collided with the board")
+	}
+	if countEvents(e.Step(Input(0).With(rotation)), EventRotate) != 1 {
+		t.Fatalf("the mino is not rotated")
+	}
+	kick = e.lastKick
+	e.Step(Input(0).With(ActionHardDrop))
+	return e.Score.LastClear, kick
+}
+
+func TestTSpinDouble(t *testing.T) {
+	rows := []string{
+		"##........",
+		"#...######",
+		"##.#######",
+	}
+	got, kick := lockTSpin(t, rows, Angle90, 1, 3, ActionRotateRight)
+	if got.Spin != SpinFull || got.Lines != 2 || kick != 0 {
+		t.Errorf("got %v with kick %d, want T-Spin Double without kick", got, kick)
+	}
+}
+
+func TestTSpinTriple(t *testing.T) {
+	// The T slides under the overhang and rotates into the slot by the last kick
+	rows := []string{
+		"....#.....",
+		"##...#####",
+		"####.#####",
+		"###..#####",
+		"####.#####",
+	}
+	got, kick := lockTSpin(t, rows, Angle0, 2, 5, ActionRotateLeft)
+	if got.Spin != SpinFull || got.Lines != 3 || kick != TSPIN_FULL_KICK {
+		t.Errorf("got %v with kick %d, want T-Spin Triple with kick %d", got, kick, TSPIN_FULL_KICK)
+	}
+}
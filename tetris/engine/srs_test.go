@@ -0,0 +1,150 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// The published SRS kicks as (x, y) with y up, keyed by the transition
+var publishedKicks = map[string][5][2]int{
+	"JLSTZ 0>R": {{0, 0}, {-1, 0}, {-1, +1}, {0, -2}, {-1, -2}},
+	"JLSTZ R>0": {{0, 0}, {+1, 0}, {+1, -1}, {0, +2}, {+1, +2}},
+	"JLSTZ R>2": {{0, 0}, {+1, 0}, {+1, -1}, {0, +2}, {+1, +2}},
+	"JLSTZ 2>R": {{0, 0}, {-1, 0}, {-1, +1}, {0, -2}, {-1, -2}},
+	"JLSTZ 2>L": {{0, 0}, {+1, 0}, {+1, +1}, {0, -2}, {+1, -2}},
+	"JLSTZ L>2": {{0, 0}, {-1, 0}, {-1, -1}, {0, +2}, {-1, +2}},
+	"JLSTZ L>0": {{0, 0}, {-1, 0}, {-1, -1}, {0, +2}, {-1, +2}},
+	"JLSTZ 0>L": {{0, 0}, {+1, 0}, {+1, +1}, {0, -2}, {+1, -2}},
+	"I 0>R":     {{0, 0}, {-2, 0}, {+1, 0}, {-2, -1}, {+1, +2}},
+	"I R>0":     {{0, 0}, {+2, 0}, {-1, 0}, {+2, +1}, {-1, -2}},
+	"I R>2":     {{0, 0}, {-1, 0}, {+2, 0}, {-1, +2}, {+2, -1}},
+	"I 2>R":     {{0, 0}, {+1, 0}, {-2, 0}, {+1, -2}, {-2, +1}},
+	"I 2>L":     {{0, 0}, {+2, 0}, {-1, 0}, {+2, +1}, {-1, -2}},
+	"I L>2":     {{0, 0}, {-2, 0}, {+1, 0}, {-2, -1}, {+1, +2}},
+	"I L>0":     {{0, 0}, {+1, 0}, {-2, 0}, {+1, -2}, {-2, +1}},
+	"I 0>L":     {{0, 0}, {-1, 0}, {+2, 0}, {-1, +2}, {+2, -1}},
+}
+
+// Return the cells of the board the mino occupies
+func minoCells(mino AbstractMino) [][2]int {
+	cells := [][2]int{}
+	for dy, row := range mino.Shape() {
+		for dx, cell := range row {
+			if cell != 0 {
+				cells = append(cells, [2]int{mino.X() + dx, mino.Y() + dy})
+			}
+		}
+	}
+	return cells
+}
+
+// Return the mino rotated in place and then moved by the published offset
+func publishedKick(mino AbstractMino, clockwise bool, offset [2]int) AbstractMino {
+	if clockwise {
+		mino = mino.rotateRight()
+	} else {
+		mino = mino.rotateLeft()
+	}
+	return translate(mino, Offset{offset[0], offset[1]})
+}
+
+func TestSRSConformance(t *testing.T) {
+	srs := RotationSystemByName(ROTATION_SRS)
+	states := "0R2L"
+
+	for _, mino := range []AbstractMino{NewMinoI(), NewMinoJ(), NewMinoL(), NewMinoS(), NewMinoT(), NewMinoZ()} {
+		group := "JLSTZ"
+		if mino.Type() == MinoTypeI {
+			group = "I"
+		}
+		for angle := range Angle(4) {
+			for _, clockwise := range []bool{true, false} {
+				next := (angle + 1) % 4
+				if !clockwise {
+					next = (angle + 3) % 4
+				}
+				transition := fmt.Sprintf("%s %c>%c", group, states[angle], states[next])
+				offsets := publishedKicks[transition]
+
+				for kick := range offsets {
+					t.Run(fmt.Sprintf("%v %s kick %d", mino.Type(), transition[len(group)+1:], kick), func(t *testing.T) {
+						// Put the mino in the middle of the board and block every earlier kick by a cell which the expected one does not use
+						current := placeMino(mino, angle, 3, 12)
+						want := publishedKick(current, clockwise, offsets[kick])
+						b := NewBoard()
+						for _, earlier := range offsets[:kick] {
+							for _, cell := range minoCells(publishedKick(current, clockwise, earlier)) {
+								if !slices.Contains(minoCells(want), cell) {
+									b[cell[1]][cell[0]] = WALL_COLOR
+									break
+								}
+							}
+						}
+
+						got, rotated := firstFit(&b, srs, current, clockwise)
+						if got != kick {
+							t.Fatalf("got kick %d, want %d", got, kick)
+						}
+						if rotated.X() != want.X() || rotated.Y() != want.Y() || rotated.Angle() != want.Angle() {
+							t.Errorf("got (%d, %d, %d), want (%d, %d, %d)",
+								rotated.X(), rotated.Y(), rotated.Angle(), want.X(), want.Y(), want.Angle())
+						}
+					})
+				}
+			}
+		}
+	}
+}
+
+func TestSRSBlocked(t *testing.T) {
+	// T surrounded by blocks cannot rotate at all
+	b := newBoardFromRows(
+		"##########",
+		"##########",
+		"###...####",
+		"####.#####",
+		"##########",
+		"##########",
+	)
+	mino := placeMino(NewMinoT(), Angle180, 3, 4)
+	for _, clockwise := range []bool{true, false} {
+		if kick, _ := firstFit(&b, RotationSystemByName(ROTATION_SRS), mino, clockwise); kick != -1 {
+			t.Errorf("got kick %d, want no rotation", kick)
+		}
+	}
+}
+
+func TestReadKickTables(t *testing.T) {
+	data, err := json.Marshal(SRS_KICK_TABLES)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadKickTables(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The loaded tables rotate minos in the same way as the built-in ones
+	b := newBoardFromRows(
+		"#.........",
+		"#.........",
+		"##........",
+	)
+	for _, mino := range Minos {
+		current := placeMino(mino, Angle90, -1, 3)
+		for _, clockwise := range []bool{true, false} {
+			wantKick, want := firstFit(&b, RotationSystemByName(ROTATION_SRS), current, clockwise)
+			gotKick, rotated := firstFit(&b, NewRotationSystem(Rules{RotationSystem: ROTATION_SRS, Kicks: got}), current, clockwise)
+			if gotKick != wantKick || want != nil && (rotated.X() != want.X() || rotated.Y() != want.Y()) {
+				t.Errorf("got kick %d for %v, want %d", gotKick, mino.Type(), wantKick)
+			}
+		}
+	}
+
+	if _, err := ReadKickTables(bytes.NewReader([]byte(`{"right": {"P": []}}`))); err == nil {
+		t.Errorf("got no error for an unknown mino")
+	}
+}
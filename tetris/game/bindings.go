@@ -0,0 +1,134 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/okayama-daiki/tetris/tetris/engine"
+)
+
+// Control is what the player can do with the keys: the actions of the engine and restarting the game
+type Control int
+
+const (
+	ControlMoveLeft    = Control(engine.ActionMoveLeft)
+	ControlMoveRight   = Control(engine.ActionMoveRight)
+	ControlSoftDrop    = Control(engine.ActionSoftDrop)
+	ControlHardDrop    = Control(engine.ActionHardDrop)
+	ControlRotateRight = Control(engine.ActionRotateRight)
+	ControlRotateLeft  = Control(engine.ActionRotateLeft)
+	ControlHold        = Control(engine.ActionHold)
+	ControlRotate180   = Control(engine.ActionRotate180)
+	ControlRestart     = Control(engine.ActionCount)
+	ControlCount       = ControlRestart + 1
+)
+
+const (
+	// Frames for which the restart keys must be held
+	RESTART_PRESS_DURATION = 30
+)
+
+var controlNames = [ControlCount]string{
+	"Move Left",
+	"Move Right",
+	"Soft Drop",
+	"Hard Drop",
+	"Rotate Right",
+	"Rotate Left",
+	"Hold",
+	"Rotate 180",
+	"Restart",
+}
+
+// Keys which cannot be bound since they pause the game
+var ReservedKeys = []ebiten.Key{ebiten.KeyEscape, ebiten.KeyP}
+
+func (c Control) String() string {
+	return controlNames[c]
+}
+
+func (c Control) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Control) UnmarshalText(text []byte) error {
+	i := slices.Index(controlNames[:], string(text))
+	if i < 0 {
+		return fmt.Errorf("unknown control: %s", text)
+	}
+	*c = Control(i)
+	return nil
+}
+
+// Return the action of the engine, or false if the control is handled outside of the engine
+func (c Control) Action() (engine.Action, bool) {
+	return engine.Action(c), c < Control(engine.ActionCount)
+}
+
+// KeyBindings maps each control to the keys bound to it. Any of the keys triggers the control.
+type KeyBindings map[Control][]ebiten.Key
+
+func DefaultKeyBindings() KeyBindings {
+	return KeyBindings{
+		ControlMoveLeft:    {ebiten.KeyLeft},
+		ControlMoveRight:   {ebiten.KeyRight},
+		ControlSoftDrop:    {ebiten.KeyDown},
+		ControlHardDrop:    {ebiten.KeySpace},
+		ControlRotateRight: {ebiten.KeyArrowUp, ebiten.KeyX},
+		ControlRotateLeft:  {ebiten.KeyZ},
+		ControlHold:        {ebiten.KeyC},
+		ControlRotate180:   {ebiten.KeyA},
+		ControlRestart:     {ebiten.KeyR},
+	}
+}
+
+// Conflict returns the control other than `c` which the key is already bound to
+func (b KeyBindings) Conflict(c Control, key ebiten.Key) (Control, bool) {
+	for other, keys := range b {
+		if other != c && slices.Contains(keys, key) {
+			return other, true
+		}
+	}
+	return 0, false
+}
+
+// Return true if any key of the control is pressed
+func (b KeyBindings) IsPressed(c Control) bool {
+	return slices.ContainsFunc(b[c], ebiten.IsKeyPressed)
+}
+
+// Return the longest duration for which a key of the control is held
+func (b KeyBindings) PressDuration(c Control) int {
+	d := 0
+	for _, key := range b[c] {
+		d = max(d, inpututil.KeyPressDuration(key))
+	}
+	return d
+}
+
+// Return the keys of the control joined by "/" such as "X/↑"
+func (b KeyBindings) Label(c Control) string {
+	labels := make([]string, len(b[c]))
+	for i, key := range b[c] {
+		labels[i] = KeyLabel(key)
+	}
+	return strings.Join(labels, "/")
+}
+
+// Return the short name of the key shown to players
+func KeyLabel(key ebiten.Key) string {
+	switch key {
+	case ebiten.KeyArrowLeft:
+		return "←"
+	case ebiten.KeyArrowRight:
+		return "→"
+	case ebiten.KeyArrowUp:
+		return "↑"
+	case ebiten.KeyArrowDown:
+		return "↓"
+	}
+	return strings.TrimPrefix(key.String(), "Digit")
+}
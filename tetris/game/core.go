@@ -3,234 +3,206 @@ package game
 import (
 	"fmt"
 	"image/color"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
 
 	"github.com/hajimehoshi/bitmapfont/v3"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/okayama-daiki/tetris/tetris/audio"
+	"github.com/okayama-daiki/tetris/tetris/engine"
+	"github.com/okayama-daiki/tetris/tetris/replay"
 )
 
 const (
-	INNER_HEIGHT  = 20
-	INNER_WIDTH   = 10
-	SENTINEL_SIZE = 1
-	CELL_SIZE     = 25
-	MARGIN        = 3
-	OUTER_HEIGHT  = MARGIN + INNER_HEIGHT + SENTINEL_SIZE
-	OUTER_WIDTH   = SENTINEL_SIZE + INNER_WIDTH + SENTINEL_SIZE
+	CELL_SIZE        = 25
+	DEFAULT_PREVIEWS = 6
+	// Height of the next panel in cells. The minos are shrunk when the previews do not fit in it.
+	NEXT_PANEL_HEIGHT = 19
+	// Height of each preview in cells
+	PREVIEW_HEIGHT = 3
+	// Frames for which the border flashes after a level up, switching the color every `LEVEL_UP_BLINK_FRAMES`
+	LEVEL_UP_FLASH_FRAMES = 60
+	LEVEL_UP_BLINK_FRAMES = 6
+	GARBAGE_METER_WIDTH   = 6
 )
 
-const (
-	KEY_LONG_PRESS_WAIT_TIME = 9
-	KEY_PRESS_DURATION       = 2
-)
-
-const (
-	MAX_LEVEL = 110
+var (
+	BACKGROUND_COLOR = color.RGBA{5, 5, 5, 255}
+	LINE_COLOR       = color.RGBA{75, 75, 75, 255}
+	BORDER_COLOR     = color.RGBA{240, 240, 240, 255}
+	GHOST_COLOR      = color.RGBA{30, 30, 30, 127}
+	LEVEL_UP_COLOR   = color.RGBA{255, 215, 0, 255}
+	// Colors of the incoming garbage which is inserted by the next mino and which is still delayed
+	GARBAGE_READY_COLOR   = color.RGBA{230, 40, 40, 255}
+	GARBAGE_PENDING_COLOR = color.RGBA{240, 150, 40, 255}
 )
 
 var fontFace = text.NewGoXFace(bitmapfont.Face)
 
-func NewGame(audioPlayer *audio.Player) *Game {
-	minoBag := MinoBag{}
-	return &Game{
-		MinoBag:              minoBag,
-		Board:                NewBoard(),
-		CurrentMino:          minoBag.Next(),
-		HoldingMino:          HoldingMino{Available: true},
-		CurrentLockDown:      NewLockDown(),
-		AudioPlayer:          audioPlayer,
-		CurrentDroppingSpeed: 60,
-		NormalDroppingSpeed:  60,
+// NewGame creates a game of the mode named `mode` whose minos are generated from `seed`.
+// If `seed` is 0, a new random seed is chosen every time the game (re)starts.
+func NewGame(audioPlayer *audio.Player, seed int64, mode string, handling engine.Handling, rules engine.Rules) (*Game, error) {
+	if _, err := engine.NewMode(mode); err != nil {
+		return nil, err
+	}
+	g := &Game{
+		AudioPlayer: audioPlayer,
+		seed:        seed,
+		mode:        mode,
+		handling:    handling,
+		rules:       rules,
+		KeyBindings: DefaultKeyBindings(),
+		Gamepads:    NewGamepads(nil),
+		Previews:    DEFAULT_PREVIEWS,
+	}
+	g.loadRecords()
+	g.start()
+	return g, nil
+}
+
+// NewReplayGame creates a game which is driven by the recorded inputs instead of the keyboard
+func NewReplayGame(audioPlayer *audio.Player, r *replay.Replay) (*Game, error) {
+	if _, err := engine.NewMode(r.Mode); err != nil {
+		return nil, err
 	}
+	g := &Game{
+		AudioPlayer: audioPlayer,
+		mode:        r.Mode,
+		playback:    replay.NewPlayback(r),
+		KeyBindings: DefaultKeyBindings(),
+		Gamepads:    NewGamepads(nil),
+		Previews:    DEFAULT_PREVIEWS,
+	}
+	g.loadRecords()
+	g.start()
+	return g, nil
 }
 
+// Game is an adapter which drives `engine.Engine` with the keyboard and gamepads and renders it with Ebiten
 type Game struct {
-	PutPieces            int
-	ClearedLines         int
-	FrameCount           int
-	MinoFrameCount       int
-	NormalDroppingSpeed  int
-	CurrentDroppingSpeed int
-	level                int
-	Board                Board
-	CurrentMino          AbstractMino
-	HoldingMino          HoldingMino
-	CurrentLockDown      *LockDown
-	MinoBag              MinoBag
-	Fragments            [OUTER_HEIGHT][OUTER_WIDTH]Fragment
-	AudioPlayer          *audio.Player
+	Engine      *engine.Engine
+	Fragments   [engine.OUTER_HEIGHT][engine.OUTER_WIDTH]Fragment
+	AudioPlayer *audio.Player
+	Replay      *replay.Replay // The record of the current run
+	ReplayPath  string         // If set, the replay of each run is saved to this file when the run ends
+	Records     Records
+	KeyBindings KeyBindings
+	Gamepads    *Gamepads // Updated by the owner of the game every frame
+	Previews    int       // Number of the next minos shown, or 0 to hide them
+	seed        int64
+	mode        string
+	handling    engine.Handling
+	rules       engine.Rules
+	rand        *rand.Rand
+	playback    *replay.Playback
+	lastRecords Records // The records before the current run, to be compared on the finish screen
+	flashFrames int     // Frames left to flash the border for the last level up
 }
 
-func (g *Game) restart() {
-	g.AudioPlayer.PlayClear()
-	g.PutPieces = 0
-	g.ClearedLines = 0
-	g.FrameCount = 0
-	g.Fragments = [OUTER_HEIGHT][OUTER_WIDTH]Fragment{}
-	for y := range OUTER_HEIGHT {
-		for x := range OUTER_WIDTH {
-			if g.Board[y][x] != nil {
-				g.Fragments[y][x] = NewFragment(g.Board[y][x], x, y)
-			}
-		}
+func (g *Game) start() {
+	seed, handling, rules := g.seed, g.handling, g.rules
+	switch {
+	case g.playback != nil:
+		g.playback.Rewind()
+		seed, handling, rules = g.playback.Seed, g.playback.Handling, g.playback.Rules
+	case seed == 0:
+		seed = time.Now().UnixNano()
 	}
-	g.HoldingMino = HoldingMino{Available: true}
-	g.Board = NewBoard()
-	g.MinoBag = MinoBag{}
-	g.CurrentMino = g.MinoBag.Next()
+	mode, _ := engine.NewMode(g.mode)
+	g.Engine = engine.NewEngine(seed, mode, rules)
+	g.Engine.Handling = handling
+	g.Replay = replay.New(seed, g.mode)
+	g.Replay.Handling = handling
+	g.Replay.Rules = rules
+	g.lastRecords = g.Records
+	g.flashFrames = 0
+	g.rand = rand.New(rand.NewSource(seed))
 }
 
-func (g *Game) Update() error {
-	g.AudioPlayer.Update()
-	g.FrameCount++
-	g.MinoFrameCount++
-	g.CurrentLockDown.UpdateTimer()
-	g.level = min(g.ClearedLines/10+1, MAX_LEVEL)
-	g.CurrentDroppingSpeed = max(int((0.8-float64(g.level-1)*0.05)*60), 1)
-
-	if inpututil.KeyPressDuration(ebiten.KeyR) == 30 {
-		g.restart()
+// SaveReplay writes the replay of the current run to `ReplayPath`.
+// Runs in which no mino has been put are not saved so as not to overwrite the previous run.
+func (g *Game) SaveReplay() error {
+	if g.ReplayPath == "" || g.playback != nil || g.Engine.PutPieces == 0 {
+		return nil
 	}
+	return g.Replay.Save(g.ReplayPath)
+}
 
-	// Hold
-	if inpututil.IsKeyJustPressed(ebiten.KeyC) && g.HoldingMino.Available {
-		if g.HoldingMino.AbstractMino == nil {
-			g.HoldingMino.AbstractMino = g.MinoBag.Next()
-		}
-		g.AudioPlayer.PlayHold()
-		g.CurrentMino = g.CurrentMino.Initialize()
-		g.HoldingMino.AbstractMino, g.CurrentMino = g.CurrentMino, g.HoldingMino.AbstractMino
-		g.HoldingMino.Available = false
+// Restart discards the current run and starts a new one
+func (g *Game) Restart() {
+	if err := g.SaveReplay(); err != nil {
+		log.Println("could not save replay: ", err)
 	}
-
-	// Hard drop
-	if inpututil.IsKeyJustPressed(ebiten.KeySpace) {
-		g.AudioPlayer.PlayHardDrop()
-		for nextMino := g.CurrentMino.MoveDown(); !g.Board.isCollided(nextMino); nextMino = nextMino.MoveDown() {
-			g.CurrentMino = nextMino
-		}
-		g.CurrentLockDown.Ground()
-		g.Board.Fix(g.CurrentMino)
-		clearedLines, clearedColors := g.Board.ClearLines()
-		if len(clearedLines) > 0 {
-			g.AudioPlayer.PlayClear()
-		}
-		for i, y := range clearedLines {
-			g.ClearedLines++
-			for x := range OUTER_WIDTH {
-				g.Fragments[y][x] = NewFragment(clearedColors[i][x], x, y)
+	g.AudioPlayer.PlayClear()
+	g.Fragments = [engine.OUTER_HEIGHT][engine.OUTER_WIDTH]Fragment{}
+	for y := range engine.OUTER_HEIGHT {
+		for x := range engine.OUTER_WIDTH {
+			if g.Engine.Board[y][x] != nil {
+				g.Fragments[y][x] = NewFragment(g.rand, g.Engine.Board[y][x], x, y)
 			}
 		}
-		g.PutPieces++
-		g.CurrentMino = g.MinoBag.Next()
-		if g.IsGameOver() {
-			g.restart()
-		}
-		g.HoldingMino.Available = true
-		g.MinoFrameCount = 0
-	}
-
-	// Move Left
-	if inpututil.KeyPressDuration(ebiten.KeyLeft) > KEY_LONG_PRESS_WAIT_TIME &&
-		inpututil.KeyPressDuration(ebiten.KeyLeft)%KEY_PRESS_DURATION == 0 || inpututil.IsKeyJustPressed(ebiten.KeyLeft) {
-		nextMino := g.CurrentMino.MoveLeft()
-		if !g.Board.isCollided(nextMino) {
-			g.AudioPlayer.PlayMove()
-			g.CurrentLockDown.UnGround()
-			g.CurrentLockDown.UpdateCounter()
-			g.CurrentMino = nextMino
-		}
 	}
+	g.start()
+}
 
-	// Move Right
-	if inpututil.KeyPressDuration(ebiten.KeyRight) > KEY_LONG_PRESS_WAIT_TIME &&
-		inpututil.KeyPressDuration(ebiten.KeyRight)%KEY_PRESS_DURATION == 0 || inpututil.IsKeyJustPressed(ebiten.KeyRight) {
-		nextMino := g.CurrentMino.MoveRight()
-		if !g.Board.isCollided(nextMino) {
-			g.AudioPlayer.PlayMove()
-			g.CurrentLockDown.UnGround()
-			g.CurrentLockDown.UpdateCounter()
-			g.CurrentMino = nextMino
+// Read the keyboard and gamepad state of the current frame
+func (g *Game) readInput() engine.Input {
+	var input engine.Input
+	for control := range ControlCount {
+		if action, ok := control.Action(); ok && (g.KeyBindings.IsPressed(control) || g.Gamepads.IsPressed(control)) {
+			input = input.With(action)
 		}
 	}
+	return input
+}
 
-	// Rotate right
-	if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyX) {
-		for nextMino := range g.CurrentMino.RotateRightSRS() {
-			if !g.Board.isCollided(nextMino) {
-				g.AudioPlayer.PlayRotate()
-				g.CurrentLockDown.UnGround()
-				g.CurrentLockDown.UpdateCounter()
-				g.CurrentMino = nextMino
-				break
-			}
-		}
+func (g *Game) Update() error {
+	if max(g.KeyBindings.PressDuration(ControlRestart), g.Gamepads.PressDuration(ControlRestart)) == RESTART_PRESS_DURATION {
+		g.Restart()
 	}
 
-	// Rotate left
-	if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
-		for nextMino := range g.CurrentMino.RotateLeftSSR() {
-			if !g.Board.isCollided(nextMino) {
-				g.AudioPlayer.PlayRotate()
-				g.CurrentLockDown.UnGround()
-				g.CurrentLockDown.UpdateCounter()
-				g.CurrentMino = nextMino
-				break
-			}
+	input := g.readInput()
+	if g.playback != nil {
+		var ok bool
+		if input, ok = g.playback.Next(); !ok {
+			return nil
 		}
 	}
+	g.Replay.Record(input)
+	g.flashFrames = max(g.flashFrames-1, 0)
 
-	// Soft drop
-	if inpututil.KeyPressDuration(ebiten.KeyDown) > 0 {
-		g.CurrentDroppingSpeed = max(g.NormalDroppingSpeed/20, 1)
-	}
-
-	switch {
-
-	case g.CurrentLockDown.IsFixed():
-		for nextMino := g.CurrentMino.MoveDown(); !g.Board.isCollided(nextMino); nextMino = nextMino.MoveDown() {
-			g.CurrentMino = nextMino
-		}
-		g.Board.Fix(g.CurrentMino)
-		clearedLines, clearedColors := g.Board.ClearLines()
-		if len(clearedLines) > 0 {
+	for _, event := range g.Engine.Step(input) {
+		switch event.Kind {
+		case engine.EventMove:
+			g.AudioPlayer.PlayMove()
+		case engine.EventRotate:
+			g.AudioPlayer.PlayRotate()
+		case engine.EventHold:
+			g.AudioPlayer.PlayHold()
+		case engine.EventHardDrop:
+			g.AudioPlayer.PlayHardDrop()
+		case engine.EventLineClear:
 			g.AudioPlayer.PlayClear()
-		}
-		for i, y := range clearedLines {
-			g.ClearedLines++
-			for x := range OUTER_WIDTH {
-				g.Fragments[y][x] = NewFragment(clearedColors[i][x], x, y)
+			for i, y := range event.Lines {
+				for x := range engine.OUTER_WIDTH {
+					g.Fragments[y][x] = NewFragment(g.rand, event.Colors[i][x], x, y)
+				}
 			}
-		}
-		g.PutPieces++
-		g.CurrentMino = g.MinoBag.Next()
-		if g.IsGameOver() {
-			g.restart()
-		}
-		g.CurrentLockDown.Reset()
-		g.HoldingMino.Available = true
-		g.MinoFrameCount = 0
-
-	case g.MinoFrameCount%g.CurrentDroppingSpeed == 0:
-		nextMino := g.CurrentMino.MoveDown()
-		if !g.Board.isCollided(nextMino) {
-			g.CurrentLockDown.Reset()
-			g.CurrentMino = nextMino
-		} else {
-			g.CurrentLockDown.Activate()
+		case engine.EventLevelUp:
+			g.AudioPlayer.PlayLevelUp()
+			g.flashFrames = LEVEL_UP_FLASH_FRAMES
+		case engine.EventFinish, engine.EventTopOut:
+			g.updateRecords()
 		}
 	}
 
 	return nil
 }
 
-func (g *Game) IsGameOver() bool {
-	return g.CurrentMino.Y() == 0 && g.Board.isCollided(g.CurrentMino)
-}
-
 func MakeDrawFilledRect(offsetX, offsetY float32) func(screen *ebiten.Image, x, y, width, height float32, clr color.Color, antialias bool) {
 	return func(screen *ebiten.Image, x, y, width, height float32, clr color.Color, antialias bool) {
 		vector.DrawFilledRect(screen, x+offsetX, y+offsetY, width, height, clr, antialias)
@@ -284,8 +256,8 @@ func (g *Game) drawGameBoard(screen *ebiten.Image, offsetX, offsetY float32) {
 	drawBlock := MakeDrawBlock(offsetX, offsetY)
 
 	// Animation
-	for y := range OUTER_HEIGHT {
-		for x := range OUTER_WIDTH {
+	for y := range engine.OUTER_HEIGHT {
+		for x := range engine.OUTER_WIDTH {
 			if g.Fragments[y][x].Frame > 0 {
 				g.Fragments[y][x].Frame--
 				posX, posY := g.Fragments[y][x].Position()
@@ -303,12 +275,12 @@ func (g *Game) drawGameBoard(screen *ebiten.Image, offsetX, offsetY float32) {
 	}
 
 	// Horizontal Lines
-	for y := MARGIN; y < OUTER_HEIGHT; y++ {
+	for y := engine.MARGIN; y < engine.OUTER_HEIGHT; y++ {
 		strokeLine(
 			screen,
 			CELL_SIZE,
 			float32(y*CELL_SIZE)+2,
-			float32(INNER_WIDTH+SENTINEL_SIZE)*CELL_SIZE,
+			float32(engine.INNER_WIDTH+engine.SENTINEL_SIZE)*CELL_SIZE,
 			float32(y*CELL_SIZE)+2,
 			0.5,
 			LINE_COLOR,
@@ -317,13 +289,13 @@ func (g *Game) drawGameBoard(screen *ebiten.Image, offsetX, offsetY float32) {
 	}
 
 	// Vertical Lines
-	for x := SENTINEL_SIZE; x < OUTER_WIDTH; x++ {
+	for x := engine.SENTINEL_SIZE; x < engine.OUTER_WIDTH; x++ {
 		strokeLine(
 			screen,
 			float32(x*CELL_SIZE),
-			MARGIN*CELL_SIZE,
+			engine.MARGIN*CELL_SIZE,
 			float32(x*CELL_SIZE),
-			float32(MARGIN+INNER_HEIGHT)*CELL_SIZE,
+			float32(engine.MARGIN+engine.INNER_HEIGHT)*CELL_SIZE,
 			0.5,
 			LINE_COLOR,
 			true,
@@ -331,51 +303,58 @@ func (g *Game) drawGameBoard(screen *ebiten.Image, offsetX, offsetY float32) {
 	}
 
 	// Border
+	borderColor := BORDER_COLOR
+	if (g.flashFrames/LEVEL_UP_BLINK_FRAMES)%2 == 1 {
+		borderColor = LEVEL_UP_COLOR
+	}
 	strokeLine(
 		screen,
 		CELL_SIZE,
-		MARGIN*CELL_SIZE,
+		engine.MARGIN*CELL_SIZE,
 		CELL_SIZE,
-		float32(MARGIN+INNER_HEIGHT)*CELL_SIZE,
+		float32(engine.MARGIN+engine.INNER_HEIGHT)*CELL_SIZE,
 		2,
-		BORDER_COLOR,
+		borderColor,
 		true,
 	)
 	strokeLine(
 		screen,
-		float32(SENTINEL_SIZE+INNER_WIDTH)*CELL_SIZE,
-		MARGIN*CELL_SIZE,
-		float32(SENTINEL_SIZE+INNER_WIDTH)*CELL_SIZE,
-		float32(MARGIN+INNER_HEIGHT)*CELL_SIZE,
+		float32(engine.SENTINEL_SIZE+engine.INNER_WIDTH)*CELL_SIZE,
+		engine.MARGIN*CELL_SIZE,
+		float32(engine.SENTINEL_SIZE+engine.INNER_WIDTH)*CELL_SIZE,
+		float32(engine.MARGIN+engine.INNER_HEIGHT)*CELL_SIZE,
 		2,
-		BORDER_COLOR,
+		borderColor,
 		true,
 	)
 	strokeLine(
 		screen,
 		CELL_SIZE,
-		float32(MARGIN+INNER_HEIGHT)*CELL_SIZE,
-		float32(INNER_WIDTH+SENTINEL_SIZE)*CELL_SIZE,
-		float32(MARGIN+INNER_HEIGHT)*CELL_SIZE,
+		float32(engine.MARGIN+engine.INNER_HEIGHT)*CELL_SIZE,
+		float32(engine.INNER_WIDTH+engine.SENTINEL_SIZE)*CELL_SIZE,
+		float32(engine.MARGIN+engine.INNER_HEIGHT)*CELL_SIZE,
 		2,
-		BORDER_COLOR,
+		borderColor,
 		true,
 	)
 
 	// Fixed minos
-	for y := 0; y < MARGIN+INNER_HEIGHT; y++ {
-		for x := SENTINEL_SIZE; x < INNER_WIDTH+SENTINEL_SIZE; x++ {
-			c := g.Board[y][x]
+	for y := 0; y < engine.MARGIN+engine.INNER_HEIGHT; y++ {
+		for x := engine.SENTINEL_SIZE; x < engine.INNER_WIDTH+engine.SENTINEL_SIZE; x++ {
+			c := g.Engine.Board[y][x]
 			if c != nil {
 				drawBlock(screen, x, y, c, CELL_SIZE)
 			}
 		}
 	}
 
-	// Ghost mino
-	ghostMino := g.CurrentMino
-	for ; !g.Board.isCollided(ghostMino.MoveDown()); ghostMino = ghostMino.MoveDown() {
+	// No mino falls between the lock of a mino and the spawn of the next one
+	if g.Engine.Phase != engine.PhaseFalling {
+		return
 	}
+
+	// Ghost mino
+	ghostMino := g.Engine.Ghost()
 	for dy := range len(ghostMino.Shape()) {
 		for dx := range len(ghostMino.Shape()[dy]) {
 			if ghostMino.Shape()[dy][dx] == 0 {
@@ -386,28 +365,55 @@ func (g *Game) drawGameBoard(screen *ebiten.Image, offsetX, offsetY float32) {
 	}
 
 	// Dropping mino
-	for dy := range len(g.CurrentMino.Shape()) {
-		for dx := range len(g.CurrentMino.Shape()[dy]) {
-			if g.CurrentMino.Shape()[dy][dx] == 0 {
+	for dy := range len(g.Engine.CurrentMino.Shape()) {
+		for dx := range len(g.Engine.CurrentMino.Shape()[dy]) {
+			if g.Engine.CurrentMino.Shape()[dy][dx] == 0 {
 				continue
 			}
-			drawBlock(screen, g.CurrentMino.X()+dx, g.CurrentMino.Y()+dy, g.CurrentMino.Color(), CELL_SIZE)
+			drawBlock(screen, g.Engine.CurrentMino.X()+dx, g.Engine.CurrentMino.Y()+dy, g.Engine.CurrentMino.Color(), CELL_SIZE)
+		}
+	}
+}
+
+// Draw the incoming garbage as a bar growing up from the bottom of the field on the left of the board, the oldest at the bottom
+func (g *Game) drawGarbageMeter(screen *ebiten.Image, offsetX, offsetY float32) {
+	drawFilledRect := MakeDrawFilledRect(offsetX, offsetY)
+	bottom := float32(engine.MARGIN+engine.INNER_HEIGHT) * CELL_SIZE
+	lines := 0
+	for _, garbage := range g.Engine.Incoming {
+		height := min(garbage.Lines, engine.INNER_HEIGHT-lines)
+		if height <= 0 {
+			break
+		}
+		clr := GARBAGE_PENDING_COLOR
+		if garbage.Delay == 0 {
+			clr = GARBAGE_READY_COLOR
 		}
+		drawFilledRect(
+			screen,
+			CELL_SIZE-GARBAGE_METER_WIDTH-2,
+			bottom-float32(lines+height)*CELL_SIZE,
+			GARBAGE_METER_WIDTH,
+			float32(height)*CELL_SIZE,
+			clr,
+			false,
+		)
+		lines += height
 	}
 }
 
 func (g *Game) drawHold(screen *ebiten.Image, offsetX, offsetY float32) {
 	drawBlock := MakeDrawBlock(offsetX, offsetY)
 
-	if g.HoldingMino.AbstractMino != nil {
-		for dy := range len(g.HoldingMino.Shape()) {
-			for dx := range len(g.HoldingMino.Shape()[dy]) {
-				if g.HoldingMino.Shape()[dy][dx] == 0 {
+	if g.Engine.HoldingMino.AbstractMino != nil {
+		for dy := range len(g.Engine.HoldingMino.Shape()) {
+			for dx := range len(g.Engine.HoldingMino.Shape()[dy]) {
+				if g.Engine.HoldingMino.Shape()[dy][dx] == 0 {
 					continue
 				}
 				var c color.Color = GHOST_COLOR
-				if g.HoldingMino.Available {
-					c = g.HoldingMino.Color()
+				if g.Engine.HoldingMino.Available {
+					c = g.Engine.HoldingMino.Color()
 				}
 				drawBlock(screen, dx+2, dy, c, CELL_SIZE)
 			}
@@ -418,13 +424,17 @@ func (g *Game) drawHold(screen *ebiten.Image, offsetX, offsetY float32) {
 func (g *Game) drawNext(screen *ebiten.Image, offsetX, offsetY float32) {
 	drawBlock := MakeDrawBlock(offsetX, offsetY)
 
-	for i, mino := range g.MinoBag.Sniff(6) {
+	size := float32(CELL_SIZE)
+	if height := g.Previews * PREVIEW_HEIGHT; height > NEXT_PANEL_HEIGHT {
+		size = CELL_SIZE * NEXT_PANEL_HEIGHT / float32(height)
+	}
+	for i, mino := range g.Engine.MinoBag.Sniff(g.Previews) {
 		for dy := range len(mino.Shape()) {
 			for dx := range len(mino.Shape()[dy]) {
 				if mino.Shape()[dy][dx] == 0 {
 					continue
 				}
-				drawBlock(screen, dx, dy+i*3, mino.Color(), CELL_SIZE)
+				drawBlock(screen, dx, dy+i*PREVIEW_HEIGHT, mino.Color(), size)
 			}
 		}
 	}
@@ -433,19 +443,24 @@ func (g *Game) drawNext(screen *ebiten.Image, offsetX, offsetY float32) {
 func (g *Game) drawController(screen *ebiten.Image, offsetX, offsetY float32) {
 	option := &text.DrawOptions{LayoutOptions: text.LayoutOptions{LineSpacing: 20}}
 	option.GeoM.Translate(float64(offsetX), float64(offsetY))
-	text.Draw(screen,
-		`
-←      : Move Left
-→      : Move Right
-Z      : Rotate Left
-X(↑)   : Rotate Right
-C      : Hold
-Space  : Hard Drop
-↓      : Soft Drop
-`,
-		fontFace,
-		option,
-	)
+	var lines strings.Builder
+	for control := range ControlCount {
+		fmt.Fprintf(&lines, "%-7s: %v\n", g.KeyBindings.Label(control), control)
+	}
+	text.Draw(screen, lines.String(), fontFace, option)
+}
+
+// Format the frame count as m:ss.cc
+func formatTime(frames int) string {
+	centiseconds := frames * 100 / engine.TPS
+	return fmt.Sprintf("%d:%02d.%02d", centiseconds/6000, centiseconds%6000/100, centiseconds%100)
+}
+
+func piecesPerSecond(pieces, frames int) float64 {
+	if frames == 0 {
+		return 0
+	}
+	return float64(pieces) * engine.TPS / float64(frames)
 }
 
 func (g *Game) drawScore(screen *ebiten.Image, offsetX, offsetY float32) {
@@ -454,32 +469,65 @@ func (g *Game) drawScore(screen *ebiten.Image, offsetX, offsetY float32) {
 	text.Draw(screen,
 		fmt.Sprintf(
 			`
+Score  : %d
 Pieces : %d, %.02f/s
 Lines  : %d
-Time   : %d:%02d.%02d
-Level	 : %d
+Time   : %s
+Level  : %d (%d left)
+Seed   : %d
 `,
-			g.PutPieces,
-			float32(g.PutPieces)/float32(g.FrameCount/10)*6,
-			g.ClearedLines,
-			g.FrameCount/3600,
-			g.FrameCount%3600/60,
-			g.FrameCount%60,
-			g.level,
+			g.Engine.Score.Points,
+			g.Engine.PutPieces,
+			piecesPerSecond(g.Engine.PutPieces, g.Engine.FrameCount),
+			g.Engine.ClearedLines,
+			formatTime(g.Engine.FrameCount),
+			g.Engine.Level,
+			g.Engine.LevelGoal()-g.Engine.LevelLines,
+			g.Engine.MinoBag.Seed,
 		),
 		fontFace,
 		option,
 	)
 }
 
+// Draw what is left to the goal of the mode: the time of the timed modes or the garbage of the cheese race
+func (g *Game) drawRemaining(screen *ebiten.Image, offsetX, offsetY float32) {
+	var remaining string
+	switch mode := g.Engine.Mode.(type) {
+	case *engine.Ultra:
+		remaining = fmt.Sprintf("Left\n%s", formatTime(mode.Remaining(g.Engine)))
+	case *engine.Survival:
+		remaining = fmt.Sprintf("Left\n%s", formatTime(mode.Remaining(g.Engine)))
+	case *engine.Cheese:
+		remaining = fmt.Sprintf("Garbage\n%d/%d", mode.Remaining(g.Engine), mode.Height)
+	default:
+		return
+	}
+	option := &text.DrawOptions{LayoutOptions: text.LayoutOptions{LineSpacing: 20}}
+	option.GeoM.Translate(float64(offsetX), float64(offsetY))
+	text.Draw(screen, remaining, fontFace, option)
+}
+
+func (g *Game) drawLastClear(screen *ebiten.Image, offsetX, offsetY float32) {
+	option := &text.DrawOptions{LayoutOptions: text.LayoutOptions{LineSpacing: 20}}
+	option.GeoM.Translate(float64(offsetX), float64(offsetY))
+	text.Draw(screen, strings.Join(g.Engine.Score.LastClear.Labels(), "\n"), fontFace, option)
+}
+
 func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Fill(BACKGROUND_COLOR)
 
 	g.drawHold(screen, 0, 2*CELL_SIZE)
+	g.drawLastClear(screen, 30, 5*CELL_SIZE)
 	g.drawGameBoard(screen, 6*CELL_SIZE, 0)
-	g.drawNext(screen, (6+OUTER_WIDTH)*CELL_SIZE, 2*CELL_SIZE)
+	g.drawGarbageMeter(screen, 6*CELL_SIZE, 0)
+	g.drawNext(screen, (6+engine.OUTER_WIDTH)*CELL_SIZE, 2*CELL_SIZE)
 	g.drawController(screen, 30, 10*CELL_SIZE)
 	g.drawScore(screen, 30, 18*CELL_SIZE)
+	g.drawRemaining(screen, (6+engine.OUTER_WIDTH)*CELL_SIZE, 21*CELL_SIZE)
+	if g.Engine.Finished {
+		g.drawResults(screen, 6*CELL_SIZE, 0)
+	}
 
 	// ebitenutil.DebugPrint(screen, fmt.Sprintf("fps: %f\ntps: %f", ebiten.ActualFPS(), ebiten.ActualTPS()))
 }
@@ -0,0 +1,149 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// GamepadButton is a button of the standard gamepad layout which is saved by its name
+type GamepadButton ebiten.StandardGamepadButton
+
+// Names of the buttons in the order of `ebiten.StandardGamepadButton`, following the Xbox controller
+var gamepadButtonNames = [...]string{
+	"A", "B", "X", "Y", "LB", "RB", "LT", "RT", "Back", "Start", "LS", "RS", "↑", "↓", "←", "→", "Home",
+}
+
+// The button which pauses the game and cannot be bound
+const PauseButton = GamepadButton(ebiten.StandardGamepadButtonCenterRight)
+
+func (b GamepadButton) String() string {
+	return gamepadButtonNames[b]
+}
+
+func (b GamepadButton) MarshalText() ([]byte, error) {
+	return []byte(b.String()), nil
+}
+
+func (b *GamepadButton) UnmarshalText(text []byte) error {
+	i := slices.Index(gamepadButtonNames[:], string(text))
+	if i < 0 {
+		return fmt.Errorf("unknown gamepad button: %s", text)
+	}
+	*b = GamepadButton(i)
+	return nil
+}
+
+// GamepadBindings maps each control to the buttons bound to it
+type GamepadBindings map[Control][]GamepadButton
+
+func DefaultGamepadBindings() GamepadBindings {
+	return GamepadBindings{
+		ControlMoveLeft:    {GamepadButton(ebiten.StandardGamepadButtonLeftLeft)},
+		ControlMoveRight:   {GamepadButton(ebiten.StandardGamepadButtonLeftRight)},
+		ControlSoftDrop:    {GamepadButton(ebiten.StandardGamepadButtonLeftBottom)},
+		ControlHardDrop:    {GamepadButton(ebiten.StandardGamepadButtonLeftTop)},
+		ControlRotateRight: {GamepadButton(ebiten.StandardGamepadButtonRightRight)},
+		ControlRotateLeft:  {GamepadButton(ebiten.StandardGamepadButtonRightBottom)},
+		ControlHold:        {GamepadButton(ebiten.StandardGamepadButtonFrontTopLeft), GamepadButton(ebiten.StandardGamepadButtonFrontTopRight)},
+		ControlRotate180:   {GamepadButton(ebiten.StandardGamepadButtonRightLeft)},
+		ControlRestart:     {GamepadButton(ebiten.StandardGamepadButtonCenterLeft)},
+	}
+}
+
+// Used for the gamepads without their own bindings, and never modified
+var defaultGamepadBindings = DefaultGamepadBindings()
+
+// Conflict returns the control other than `c` which the button is already bound to
+func (b GamepadBindings) Conflict(c Control, button GamepadButton) (Control, bool) {
+	for other, buttons := range b {
+		if other != c && slices.Contains(buttons, button) {
+			return other, true
+		}
+	}
+	return 0, false
+}
+
+// Return the buttons of the control joined by "/" such as "LB/RB"
+func (b GamepadBindings) Label(c Control) string {
+	labels := make([]string, len(b[c]))
+	for i, button := range b[c] {
+		labels[i] = button.String()
+	}
+	return strings.Join(labels, "/")
+}
+
+// Gamepads keeps track of the connected gamepads with the standard layout.
+//   - Gamepads can be connected and disconnected at any time, and `Update` must be called every frame to notice it
+//   - Each device has its own bindings in `Bindings` keyed by its SDL ID, so that they survive reconnection
+//   - The default bindings are used for the devices which do not have their own
+type Gamepads struct {
+	Bindings map[string]GamepadBindings
+	ids      []ebiten.GamepadID
+}
+
+func NewGamepads(bindings map[string]GamepadBindings) *Gamepads {
+	return &Gamepads{Bindings: bindings}
+}
+
+// Update handles the gamepads connected or disconnected in this frame
+func (g *Gamepads) Update() {
+	for _, id := range inpututil.AppendJustConnectedGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			log.Printf("gamepad %q is not supported since it has no standard layout", ebiten.GamepadName(id))
+			continue
+		}
+		g.ids = append(g.ids, id)
+	}
+	g.ids = slices.DeleteFunc(g.ids, inpututil.IsGamepadJustDisconnected)
+}
+
+// Return the connected gamepads in the order of connection
+func (g *Gamepads) IDs() []ebiten.GamepadID {
+	return g.ids
+}
+
+// Return the bindings of the device
+func (g *Gamepads) BindingsOf(id ebiten.GamepadID) GamepadBindings {
+	if bindings, ok := g.Bindings[ebiten.GamepadSDLID(id)]; ok {
+		return bindings
+	}
+	return defaultGamepadBindings
+}
+
+// Return true if any button of the control is pressed on any gamepad
+func (g *Gamepads) IsPressed(c Control) bool {
+	for _, id := range g.ids {
+		for _, button := range g.BindingsOf(id)[c] {
+			if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButton(button)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Return the longest duration for which a button of the control is held on any gamepad
+func (g *Gamepads) PressDuration(c Control) int {
+	d := 0
+	for _, id := range g.ids {
+		for _, button := range g.BindingsOf(id)[c] {
+			d = max(d, inpututil.StandardGamepadButtonPressDuration(id, ebiten.StandardGamepadButton(button)))
+		}
+	}
+	return d
+}
+
+// Return true if the button is just pressed on any gamepad
+func (g *Gamepads) IsJustPressed(button GamepadButton) bool {
+	for _, id := range g.ids {
+		if inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButton(button)) {
+			return true
+		}
+	}
+	return false
+}
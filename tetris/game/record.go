@@ -0,0 +1,159 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/okayama-daiki/tetris/tetris/engine"
+	"github.com/okayama-daiki/tetris/tetris/storage"
+)
+
+const (
+	RECORDS_FILE       = "records.json"
+	ULTRA_RANKING_SIZE = 10
+)
+
+// Records are the personal bests kept across sessions
+type Records struct {
+	// The best time of the sprint by the rules of the mechanics (see `sprintKey`), which are not comparable with each other
+	Sprint map[string]SprintRecord `json:"sprint,omitempty"`
+	// The best score of the marathon by the start level and the level goal (see `marathonKey`), which are not comparable with each other
+	Marathon map[string]MarathonRecord `json:"marathon,omitempty"`
+	// The best time of the cheese race by the garbage height and the messiness (see `cheeseKey`), which are not comparable with each other
+	Cheese map[string]CheeseRecord `json:"cheese,omitempty"`
+	Ultra  []UltraRecord           `json:"ultra,omitempty"` // Sorted by the score in descending order
+}
+
+type SprintRecord struct {
+	Frames int   `json:"frames"`
+	Pieces int   `json:"pieces"`
+	Splits []int `json:"splits"`
+}
+
+// Return the key of the sprint records such as "guideline/extended/30/15" from the gravity and the lock down
+func sprintKey(rules engine.Rules) string {
+	return fmt.Sprintf("%s/%s/%d/%d", rules.Gravity, rules.LockDown, rules.LockDelay, rules.LockResets)
+}
+
+type MarathonRecord struct {
+	Score  int `json:"score"`
+	Frames int `json:"frames"`
+	Lines  int `json:"lines"`
+}
+
+// Return the key of the marathon records such as "1/fixed"
+func marathonKey(mode *engine.Marathon) string {
+	return fmt.Sprintf("%d/%s", mode.StartLevel, mode.Goal)
+}
+
+type CheeseRecord struct {
+	Frames int `json:"frames"`
+	Pieces int `json:"pieces"`
+}
+
+// Return the key of the cheese records such as "10/50" from the garbage height and the messiness
+func cheeseKey(mode *engine.Cheese) string {
+	return fmt.Sprintf("%d/%d", mode.Height, mode.Messiness)
+}
+
+type UltraRecord struct {
+	Score  int       `json:"score"`
+	Lines  int       `json:"lines"`
+	Pieces int       `json:"pieces"`
+	Date   time.Time `json:"date"`
+}
+
+// Return the 1-based rank of the score among the ranking
+func ultraRank(ranking []UltraRecord, score int) int {
+	for i, r := range ranking {
+		if r.Score < score {
+			return i + 1
+		}
+	}
+	return len(ranking) + 1
+}
+
+func (g *Game) loadRecords() {
+	if err := storage.Load(RECORDS_FILE, &g.Records); err != nil {
+		log.Println("could not load records: ", err)
+	}
+}
+
+// Update the personal best with the run which is over and save it if it is improved.
+//   - The sprint and the cheese race count only the finished runs since their records are the time to finish
+//   - The marathon and the ultra count the runs topped out as well since their records are the score
+//
+// Replays are not counted since they are not played by the player.
+func (g *Game) updateRecords() {
+	if g.playback != nil {
+		return
+	}
+	switch mode := g.Engine.Mode.(type) {
+	case *engine.Sprint:
+		key := sprintKey(g.Engine.Rules)
+		if best, ok := g.Records.Sprint[key]; g.Engine.IsGameOver() || ok && best.Frames <= g.Engine.FrameCount {
+			return
+		}
+		// Copy the map so as not to change the records before the run kept in `lastRecords`
+		records := maps.Clone(g.Records.Sprint)
+		if records == nil {
+			records = map[string]SprintRecord{}
+		}
+		records[key] = SprintRecord{
+			Frames: g.Engine.FrameCount,
+			Pieces: g.Engine.PutPieces,
+			Splits: mode.Splits,
+		}
+		g.Records.Sprint = records
+	case *engine.Marathon:
+		key := marathonKey(mode)
+		if best, ok := g.Records.Marathon[key]; ok && best.Score >= g.Engine.Score.Points {
+			return
+		}
+		// Copy the map so as not to change the records before the run kept in `lastRecords`
+		records := maps.Clone(g.Records.Marathon)
+		if records == nil {
+			records = map[string]MarathonRecord{}
+		}
+		records[key] = MarathonRecord{
+			Score:  g.Engine.Score.Points,
+			Frames: g.Engine.FrameCount,
+			Lines:  g.Engine.ClearedLines,
+		}
+		g.Records.Marathon = records
+	case *engine.Cheese:
+		key := cheeseKey(mode)
+		if best, ok := g.Records.Cheese[key]; g.Engine.IsGameOver() || ok && best.Frames <= g.Engine.FrameCount {
+			return
+		}
+		// Copy the map so as not to change the records before the run kept in `lastRecords`
+		records := maps.Clone(g.Records.Cheese)
+		if records == nil {
+			records = map[string]CheeseRecord{}
+		}
+		records[key] = CheeseRecord{Frames: g.Engine.FrameCount, Pieces: g.Engine.PutPieces}
+		g.Records.Cheese = records
+	case *engine.Ultra:
+		rank := ultraRank(g.Records.Ultra, g.Engine.Score.Points)
+		if rank > ULTRA_RANKING_SIZE {
+			return
+		}
+		// Insert into a copy since the ranking may have room to be changed in place, which `lastRecords` shares
+		g.Records.Ultra = slices.Insert(slices.Clone(g.Records.Ultra), rank-1, UltraRecord{
+			Score:  g.Engine.Score.Points,
+			Lines:  g.Engine.ClearedLines,
+			Pieces: g.Engine.PutPieces,
+			Date:   time.Now(),
+		})
+		g.Records.Ultra = g.Records.Ultra[:min(len(g.Records.Ultra), ULTRA_RANKING_SIZE)]
+	default:
+		return
+	}
+
+	if err := storage.Save(RECORDS_FILE, g.Records); err != nil {
+		log.Println("could not save records: ", err)
+	}
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/okayama-daiki/tetris/tetris/engine"
+)
+
+func TestUltraRanking(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	g := &Game{seed: 1, mode: "ultra", rules: engine.DefaultRules()}
+	// Rankings loaded from JSON have room to grow in place
+	g.Records.Ultra = make([]UltraRecord, 0, ULTRA_RANKING_SIZE)
+	g.Records.Ultra = append(g.Records.Ultra, UltraRecord{Score: 300}, UltraRecord{Score: 100})
+
+	// Two games in a row, each ranked against the records before it
+	for _, tt := range []struct{ score, rank int }{{200, 2}, {150, 3}} {
+		g.start()
+		g.Engine.Score.Points = tt.score
+		g.Engine.Finished = true
+		g.updateRecords()
+
+		if got := ultraRank(g.lastRecords.Ultra, tt.score); got != tt.rank {
+			t.Errorf("score %d: got rank %d, want %d", tt.score, got, tt.rank)
+		}
+		if want := fmt.Sprintf("> %2d : %d", tt.rank, tt.score); !strings.Contains(g.resultsText(), want) {
+			t.Errorf("score %d: got %q, want %q marked", tt.score, g.resultsText(), want)
+		}
+	}
+}
+
+func TestRecordsOnTopOut(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	tests := []struct {
+		mode string
+		want bool // Whether the run topped out is recorded
+	}{
+		{"sprint", false},
+		{"marathon", true},
+		{"cheese", false},
+		{"ultra", true},
+	}
+	for _, tt := range tests {
+		g := &Game{seed: 1, mode: tt.mode, rules: engine.DefaultRules()}
+		g.start()
+		g.Engine.Score.Points = 100
+		g.Engine.TopOut = engine.TopOutBlockOut
+		g.Engine.Finished = true
+		g.updateRecords()
+
+		got := len(g.Records.Sprint)+len(g.Records.Marathon)+len(g.Records.Cheese)+len(g.Records.Ultra) > 0
+		if got != tt.want {
+			t.Errorf("%s: got recorded %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestCheeseRecordsByMessiness(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	g := &Game{seed: 1, mode: "cheese", rules: engine.DefaultRules()}
+	for i, messiness := range []int{0, 100} {
+		g.rules.CheeseMessiness = messiness
+		g.start()
+		// Slower than the run of the other messiness, which is not compared with
+		g.Engine.FrameCount = 100 * (i + 1)
+		g.Engine.Finished = true
+		g.updateRecords()
+
+		if !strings.Contains(g.resultsText(), "New Personal Best!") {
+			t.Errorf("messiness %d: got %q, want a new personal best", messiness, g.resultsText())
+		}
+	}
+	if got := len(g.Records.Cheese); got != 2 {
+		t.Errorf("got %d cheese records, want 2", got)
+	}
+}
@@ -0,0 +1,126 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/okayama-daiki/tetris/tetris/engine"
+)
+
+var (
+	OVERLAY_COLOR = color.RGBA{5, 5, 5, 220}
+)
+
+// Format the difference of frame counts as +s.cc or -s.cc
+func formatDiff(frames int) string {
+	sign := "+"
+	if frames < 0 {
+		sign, frames = "-", -frames
+	}
+	centiseconds := frames * 100 / engine.TPS
+	return fmt.Sprintf("%s%d.%02d", sign, centiseconds/100, centiseconds%100)
+}
+
+func (g *Game) resultsText() string {
+	var b strings.Builder
+	if g.Engine.IsGameOver() {
+		fmt.Fprintf(&b, "GAME OVER (%v)\n\n", g.Engine.TopOut)
+	} else {
+		fmt.Fprintf(&b, "%s CLEAR\n\n", strings.ToUpper(g.Engine.Mode.Name()))
+	}
+	fmt.Fprintf(&b, "Time   : %s\n", formatTime(g.Engine.FrameCount))
+	fmt.Fprintf(&b, "Pieces : %d\n", g.Engine.PutPieces)
+	fmt.Fprintf(&b, "PPS    : %.02f\n", piecesPerSecond(g.Engine.PutPieces, g.Engine.FrameCount))
+	fmt.Fprintf(&b, "Lines  : %d\n", g.Engine.ClearedLines)
+	fmt.Fprintf(&b, "Level  : %d\n", g.Engine.Level)
+	fmt.Fprintf(&b, "Score  : %d\n", g.Engine.Score.Points)
+
+	switch mode := g.Engine.Mode.(type) {
+	case *engine.Sprint:
+		if g.Engine.IsGameOver() {
+			// The time of the run which is not finished is not compared
+			break
+		}
+		best, ok := g.lastRecords.Sprint[sprintKey(g.Engine.Rules)]
+		b.WriteString("\nSplits\n")
+		for i, frames := range mode.Splits {
+			fmt.Fprintf(&b, "  %3d  : %s", (i+1)*engine.SPRINT_SPLIT_LINES, formatTime(frames))
+			if ok && g.playback == nil && i < len(best.Splits) {
+				fmt.Fprintf(&b, " (%s)", formatDiff(frames-best.Splits[i]))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+		switch {
+		case g.playback != nil:
+			// The personal best is not compared with replays, which may be of someone else
+		case !ok || g.Engine.FrameCount < best.Frames:
+			b.WriteString("New Personal Best!\n")
+		default:
+			fmt.Fprintf(&b, "Best   : %s (%s)\n", formatTime(best.Frames), formatDiff(g.Engine.FrameCount-best.Frames))
+		}
+
+	case *engine.Marathon:
+		best, ok := g.lastRecords.Marathon[marathonKey(mode)]
+		fmt.Fprintf(&b, "\nFrom level %d, %s goal\n", mode.StartLevel, mode.Goal)
+		switch {
+		case g.playback != nil:
+		case !ok || g.Engine.Score.Points > best.Score:
+			b.WriteString("New Personal Best!\n")
+		default:
+			fmt.Fprintf(&b, "Best   : %d (%s)\n", best.Score, formatTime(best.Frames))
+		}
+
+	case *engine.Cheese:
+		if g.Engine.IsGameOver() {
+			break
+		}
+		best, ok := g.lastRecords.Cheese[cheeseKey(mode)]
+		fmt.Fprintf(&b, "\n%d rows, %d%% messiness\n", mode.Height, mode.Messiness)
+		switch {
+		case g.playback != nil:
+		case !ok || g.Engine.FrameCount < best.Frames:
+			b.WriteString("New Personal Best!\n")
+		default:
+			fmt.Fprintf(&b, "Best   : %s (%s)\n", formatTime(best.Frames), formatDiff(g.Engine.FrameCount-best.Frames))
+		}
+
+	case *engine.Survival:
+		fmt.Fprintf(&b, "Sent   : %d\n", g.Engine.SentLines)
+
+	case *engine.Ultra:
+		b.WriteString("\nRanking\n")
+		rank := ultraRank(g.lastRecords.Ultra, g.Engine.Score.Points)
+		for i, r := range g.Records.Ultra {
+			marker := " "
+			if i+1 == rank && g.playback == nil {
+				marker = ">"
+			}
+			fmt.Fprintf(&b, "%s %2d : %d\n", marker, i+1, r.Score)
+		}
+	}
+
+	b.WriteString("\nEnter : Retry\nEsc   : Menu\n")
+	return b.String()
+}
+
+// Draw the results of the finished game over the board
+func (g *Game) drawResults(screen *ebiten.Image, offsetX, offsetY float32) {
+	drawFilledRect := MakeDrawFilledRect(offsetX, offsetY)
+	drawFilledRect(
+		screen,
+		engine.SENTINEL_SIZE*CELL_SIZE,
+		engine.MARGIN*CELL_SIZE,
+		engine.INNER_WIDTH*CELL_SIZE,
+		engine.INNER_HEIGHT*CELL_SIZE,
+		OVERLAY_COLOR,
+		false,
+	)
+
+	option := &text.DrawOptions{LayoutOptions: text.LayoutOptions{LineSpacing: 20}}
+	option.GeoM.Translate(float64(offsetX)+2*CELL_SIZE, float64(offsetY)+(engine.MARGIN+1)*CELL_SIZE)
+	text.Draw(screen, g.resultsText(), fontFace, option)
+}
@@ -0,0 +1,217 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+
+	"github.com/okayama-daiki/tetris/tetris/engine"
+)
+
+const (
+	MAGIC            = "ETRP"
+	VERSION          = 12
+	MAX_MODE_LENGTH  = 64
+	MAX_RULES_LENGTH = 1 << 16
+	// Frames of the longest replay, which keeps crafted files from exhausting the memory
+	MAX_FRAMES = 24 * 60 * 60 * engine.TPS
+)
+
+var (
+	ErrInvalidFormat = errors.New("replay: invalid format")
+	// Replays of older versions cannot be played back since the same inputs play differently.
+	// Each version changed the engine as follows.
+	//   - 5: the gravity became fractional instead of counting whole frames
+	//   - 6: IRS and IHS were added and turned on by default
+	//   - 7: DAS cut of 0 no longer suspends the auto repeat in the frame a mino spawns
+	//   - 8: the drop points are multiplied by the level
+	//   - 9: the drought order of TGM3 starts empty as in the reference randomizer
+	//   - 10: the lock down became a choice of policies, and the extended placement without resets keeps the lock delay
+	//   - 11: the cheese is generated from a stream separate from the minos
+	//   - 12: the start level and the level goal apply only to the marathon
+	ErrOldVersion = errors.New("replay: recorded by an older version which cannot be played back")
+)
+
+// Replay is a record of a single game: the mode, the seed of the mino bag, the handling, the rules and the input of every frame.
+// The auto repeat state is not stored since the engine derives it from the held inputs and the handling.
+//
+// The file format is
+//   - `MAGIC` and `VERSION`
+//   - the seed as a varint
+//   - the name of the mode prefixed with its length as a uvarint
+//   - the handling as uvarints in the order of the fields of `engine.Handling`
+//   - the rules as JSON prefixed with its length as a uvarint, so that new rules do not change the format
+//   - run-length encoded inputs as pairs of uvarints (count, input), up to `MAX_FRAMES` in total
+type Replay struct {
+	Seed     int64
+	Mode     string
+	Handling engine.Handling
+	Rules    engine.Rules
+	Inputs   []engine.Input
+}
+
+func New(seed int64, mode string) *Replay {
+	return &Replay{Seed: seed, Mode: mode, Handling: engine.DefaultHandling(), Rules: engine.DefaultRules()}
+}
+
+// Fields of the handling in the order they are written
+func handlingFields(h *engine.Handling) []*int {
+	return []*int{&h.DAS, &h.ARR, &h.SoftDropFactor, &h.DASCut, &h.DCD}
+}
+
+func (r *Replay) Record(input engine.Input) {
+	r.Inputs = append(r.Inputs, input)
+}
+
+func (r *Replay) Write(w io.Writer) error {
+	rules, err := json.Marshal(r.Rules)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	buf := make([]byte, 0, 8*binary.MaxVarintLen64)
+
+	buf = append(buf, MAGIC...)
+	buf = append(buf, VERSION)
+	buf = binary.AppendVarint(buf, r.Seed)
+	buf = binary.AppendUvarint(buf, uint64(len(r.Mode)))
+	buf = append(buf, r.Mode...)
+	for _, field := range handlingFields(&r.Handling) {
+		buf = binary.AppendUvarint(buf, uint64(*field))
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(rules)))
+	buf = append(buf, rules...)
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(r.Inputs); {
+		j := i
+		for j < len(r.Inputs) && r.Inputs[j] == r.Inputs[i] {
+			j++
+		}
+		buf = binary.AppendUvarint(buf[:0], uint64(j-i))
+		buf = binary.AppendUvarint(buf, uint64(r.Inputs[i]))
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		i = j
+	}
+	return bw.Flush()
+}
+
+func Read(r io.Reader) (*Replay, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(MAGIC)+1)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, ErrInvalidFormat
+	}
+	version := header[len(MAGIC)]
+	if string(header[:len(MAGIC)]) != MAGIC || version > VERSION {
+		return nil, ErrInvalidFormat
+	}
+	if version < VERSION {
+		return nil, ErrOldVersion
+	}
+	seed, err := binary.ReadVarint(br)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	length, err := binary.ReadUvarint(br)
+	if err != nil || length > MAX_MODE_LENGTH {
+		return nil, ErrInvalidFormat
+	}
+	mode := make([]byte, length)
+	if _, err := io.ReadFull(br, mode); err != nil {
+		return nil, ErrInvalidFormat
+	}
+
+	replay := New(seed, string(mode))
+	for _, field := range handlingFields(&replay.Handling) {
+		v, err := binary.ReadUvarint(br)
+		if err != nil || v > math.MaxInt32 {
+			return nil, ErrInvalidFormat
+		}
+		*field = int(v)
+	}
+	length, err = binary.ReadUvarint(br)
+	if err != nil || length > MAX_RULES_LENGTH {
+		return nil, ErrInvalidFormat
+	}
+	rules := make([]byte, length)
+	if _, err := io.ReadFull(br, rules); err != nil {
+		return nil, ErrInvalidFormat
+	}
+	if err := json.Unmarshal(rules, &replay.Rules); err != nil {
+		return nil, ErrInvalidFormat
+	}
+	for {
+		count, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return replay, nil
+		}
+		if err != nil {
+			return nil, ErrInvalidFormat
+		}
+		if count > uint64(MAX_FRAMES-len(replay.Inputs)) {
+			return nil, ErrInvalidFormat
+		}
+		input, err := binary.ReadUvarint(br)
+		if err != nil || input >= 1<<engine.ActionCount {
+			return nil, ErrInvalidFormat
+		}
+		for range count {
+			replay.Record(engine.Input(input))
+		}
+	}
+}
+
+func (r *Replay) Save(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func Load(name string) (*Replay, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Playback feeds the recorded inputs frame by frame
+type Playback struct {
+	*Replay
+	frame int
+}
+
+func NewPlayback(replay *Replay) *Playback {
+	return &Playback{Replay: replay}
+}
+
+// Next returns the input of the next frame, or false if the replay has ended
+func (p *Playback) Next() (engine.Input, bool) {
+	if p.frame >= len(p.Inputs) {
+		return 0, false
+	}
+	input := p.Inputs[p.frame]
+	p.frame++
+	return input, true
+}
+
+func (p *Playback) Rewind() {
+	p.frame = 0
+}
@@ -0,0 +1,94 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"testing"
+
+	"github.com/okayama-daiki/tetris/tetris/engine"
+)
+
+func TestWriteRead(t *testing.T) {
+	r := New(-12345, "sprint")
+	r.Handling = engine.Handling{DAS: 7, ARR: 0, SoftDropFactor: engine.INFINITE_SOFT_DROP, DASCut: 1, DCD: 2}
+	r.Rules.Kick180 = engine.KICK_180_TETRIO
+	r.Rules.AllSpin = true
+	for i := range 1000 {
+		r.Record(engine.Input(i / 7 % 5))
+	}
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Seed != r.Seed || got.Mode != r.Mode || len(got.Inputs) != len(r.Inputs) {
+		t.Fatalf("got %d, %q and %d inputs, want %d, %q and %d", got.Seed, got.Mode, len(got.Inputs), r.Seed, r.Mode, len(r.Inputs))
+	}
+	if got.Handling != r.Handling || got.Rules != r.Rules {
+		t.Errorf("got %+v and %+v, want %+v and %+v", got.Handling, got.Rules, r.Handling, r.Rules)
+	}
+	for i := range r.Inputs {
+		if got.Inputs[i] != r.Inputs[i] {
+			t.Fatalf("got %v at frame %d, want %v", got.Inputs[i], i, r.Inputs[i])
+		}
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	if _, err := Read(bytes.NewReader([]byte("not a replay"))); err != ErrInvalidFormat {
+		t.Errorf("got %v, want %v", err, ErrInvalidFormat)
+	}
+	for _, version := range []byte{4, VERSION - 1} {
+		if _, err := Read(bytes.NewReader([]byte{MAGIC[0], MAGIC[1], MAGIC[2], MAGIC[3], version})); err != ErrOldVersion {
+			t.Errorf("version %d: got %v, want %v", version, err, ErrOldVersion)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		count uint64
+		input uint64
+	}{
+		{"too long run", 1 << 40, 0},
+		{"too many frames", MAX_FRAMES + 1, 0},
+		{"unknown action", 1, 1 << engine.ActionCount},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := New(0, "endless").Write(&buf); err != nil {
+			t.Fatal(err)
+		}
+		b := binary.AppendUvarint(buf.Bytes(), tt.count)
+		b = binary.AppendUvarint(b, tt.input)
+		if _, err := Read(bytes.NewReader(b)); err != ErrInvalidFormat {
+			t.Errorf("%s: got %v, want %v", tt.name, err, ErrInvalidFormat)
+		}
+	}
+}
+
+func TestPlayback(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	recorded := engine.NewEngine(7, engine.Endless{}, engine.DefaultRules())
+	r := New(7, "endless")
+	for range 3000 {
+		input := engine.Input(rnd.Intn(1 << engine.ActionCount))
+		r.Record(input)
+		recorded.Step(input)
+	}
+
+	played := engine.NewEngine(r.Seed, engine.Endless{}, r.Rules)
+	p := NewPlayback(r)
+	for input, ok := p.Next(); ok; input, ok = p.Next() {
+		played.Step(input)
+	}
+
+	if played.Board != recorded.Board || played.PutPieces != recorded.PutPieces {
+		t.Errorf("got a different game from the replay")
+	}
+}
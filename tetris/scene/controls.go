@@ -0,0 +1,177 @@
+package scene
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/okayama-daiki/tetris/tetris/game"
+	"github.com/okayama-daiki/tetris/tetris/storage"
+)
+
+const (
+	MAX_KEYS_PER_CONTROL = 3
+)
+
+// Controls edits the key and gamepad bindings in `Manager.Settings` and saves them when leaving.
+//   - Enter waits for the next key or gamepad button and binds it to the control under the cursor
+//   - A gamepad button is bound only for the gamepad it is pressed on, which becomes the one shown
+//   - Backspace unbinds the last key and Delete the last button of the control, but at least one key is kept
+//   - Keys and buttons bound to another control, `game.ReservedKeys` and `game.PauseButton` are refused
+type Controls struct {
+	menu      Menu
+	capturing bool
+	message   string
+	device    ebiten.GamepadID
+	hasDevice bool
+	keys      []ebiten.Key
+	buttons   []ebiten.StandardGamepadButton
+}
+
+func NewControls() *Controls {
+	return &Controls{menu: Menu{Title: "CONTROLS"}}
+}
+
+// The item below the controls which restores the default bindings
+func (s *Controls) isResetItem() bool {
+	return s.menu.Cursor == int(game.ControlCount)
+}
+
+// Return the bindings of the shown gamepad, which are copied from the default ones to be edited
+func (s *Controls) deviceBindings(m *Manager) game.GamepadBindings {
+	id := ebiten.GamepadSDLID(s.device)
+	bindings, ok := m.Settings.GamepadBindings[id]
+	if !ok {
+		bindings = game.DefaultGamepadBindings()
+		m.Settings.GamepadBindings[id] = bindings
+	}
+	return bindings
+}
+
+func (s *Controls) Update(m *Manager) error {
+	if ids := m.Gamepads.IDs(); !slices.Contains(ids, s.device) || !s.hasDevice {
+		s.hasDevice = len(ids) > 0
+		if s.hasDevice {
+			s.device = ids[0]
+		}
+	}
+
+	if s.capturing {
+		s.capture(m)
+		return nil
+	}
+
+	keyBindings := m.Settings.KeyBindings
+	control := game.Control(s.menu.Cursor)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		if err := storage.Save(SETTINGS_FILE, m.Settings); err != nil {
+			log.Println("could not save settings: ", err)
+		}
+		m.Switch(NewTitle())
+		return nil
+	case inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && !s.isResetItem():
+		if len(keyBindings[control]) > 1 {
+			keyBindings[control] = keyBindings[control][:len(keyBindings[control])-1]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyDelete) && !s.isResetItem() && s.hasDevice:
+		if bindings := s.deviceBindings(m); len(bindings[control]) > 0 {
+			bindings[control] = bindings[control][:len(bindings[control])-1]
+		}
+	}
+
+	s.menu.Items = s.menu.Items[:0]
+	for c := range game.ControlCount {
+		item := fmt.Sprintf("%-13v: %-12s", c, keyBindings.Label(c))
+		if s.hasDevice {
+			item += " " + m.Gamepads.BindingsOf(s.device).Label(c)
+		}
+		s.menu.Items = append(s.menu.Items, item)
+	}
+	s.menu.Items = append(s.menu.Items, "Reset to Default")
+	if !s.menu.Update() {
+		return nil
+	}
+
+	s.message = ""
+	if s.isResetItem() {
+		m.Settings.KeyBindings = game.DefaultKeyBindings()
+		clear(m.Settings.GamepadBindings)
+		return nil
+	}
+	s.capturing = true
+	s.message = fmt.Sprintf("Press a key or button for %v (Esc : Cancel)", control)
+	return nil
+}
+
+// Bind the key or gamepad button pressed in this frame to the control under the cursor
+func (s *Controls) capture(m *Manager) {
+	control := game.Control(s.menu.Cursor)
+
+	s.keys = inpututil.AppendJustPressedKeys(s.keys[:0])
+	if len(s.keys) > 0 {
+		s.capturing = false
+		s.message = bindKey(m.Settings.KeyBindings, control, s.keys[0])
+		return
+	}
+
+	for _, id := range m.Gamepads.IDs() {
+		s.buttons = inpututil.AppendJustPressedStandardGamepadButtons(id, s.buttons[:0])
+		if len(s.buttons) > 0 {
+			s.capturing = false
+			s.device, s.hasDevice = id, true
+			s.message = bindButton(s.deviceBindings(m), control, game.GamepadButton(s.buttons[0]))
+			return
+		}
+	}
+}
+
+// Bind the key to the control and return the message to show
+func bindKey(bindings game.KeyBindings, control game.Control, key ebiten.Key) string {
+	if key == ebiten.KeyEscape || slices.Contains(bindings[control], key) {
+		return ""
+	}
+	if slices.Contains(game.ReservedKeys, key) {
+		return fmt.Sprintf("%s is reserved for pause", game.KeyLabel(key))
+	}
+	if other, ok := bindings.Conflict(control, key); ok {
+		return fmt.Sprintf("%s is already bound to %v", game.KeyLabel(key), other)
+	}
+	if len(bindings[control]) >= MAX_KEYS_PER_CONTROL {
+		return fmt.Sprintf("Up to %d keys can be bound. Press Backspace to unbind one.", MAX_KEYS_PER_CONTROL)
+	}
+	bindings[control] = append(bindings[control], key)
+	return ""
+}
+
+// Bind the gamepad button to the control and return the message to show
+func bindButton(bindings game.GamepadBindings, control game.Control, button game.GamepadButton) string {
+	if slices.Contains(bindings[control], button) {
+		return ""
+	}
+	if button == game.PauseButton {
+		return fmt.Sprintf("%v is reserved for pause", button)
+	}
+	if other, ok := bindings.Conflict(control, button); ok {
+		return fmt.Sprintf("%v is already bound to %v", button, other)
+	}
+	if len(bindings[control]) >= MAX_KEYS_PER_CONTROL {
+		return fmt.Sprintf("Up to %d buttons can be bound. Press Delete to unbind one.", MAX_KEYS_PER_CONTROL)
+	}
+	bindings[control] = append(bindings[control], button)
+	return ""
+}
+
+func (s *Controls) Draw(screen *ebiten.Image) {
+	screen.Fill(game.BACKGROUND_COLOR)
+	s.menu.Draw(screen)
+	if s.hasDevice {
+		drawText(screen, "Gamepad: "+ebiten.GamepadName(s.device), 150, 190, 1)
+	} else {
+		drawText(screen, "No gamepad connected", 150, 190, 1)
+	}
+	drawText(screen, s.message, 30, SCREEN_HEIGHT-70, 1)
+	drawHint(screen, "Enter : Add    BS : Remove Key    Del : Remove Button    Esc : Back")
+}
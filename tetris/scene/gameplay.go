@@ -0,0 +1,138 @@
+package scene
+
+import (
+	"log"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/okayama-daiki/tetris/tetris/game"
+)
+
+const (
+	PAUSE_MUSIC_VOLUME_RATIO = 0.3
+)
+
+// Gameplay runs the game until it is paused or finished.
+// The game is paused by Esc, P or the start button of a gamepad, and automatically when the window loses focus.
+type Gameplay struct {
+	game *game.Game
+}
+
+func NewGameplay(g *game.Game) *Gameplay {
+	return &Gameplay{game: g}
+}
+
+func (s *Gameplay) Update(m *Manager) error {
+	if isPausePressed(m) || !ebiten.IsFocused() {
+		s.pause(m)
+		return nil
+	}
+	if err := s.game.Update(); err != nil {
+		return err
+	}
+	if s.game.Engine.Finished {
+		m.Switch(NewResults(s))
+	}
+	return nil
+}
+
+func (s *Gameplay) Draw(screen *ebiten.Image) {
+	s.game.Draw(screen)
+}
+
+// Return true if any key or button to pause or resume the game is just pressed
+func isPausePressed(m *Manager) bool {
+	return slices.ContainsFunc(game.ReservedKeys, inpututil.IsKeyJustPressed) || m.Gamepads.IsJustPressed(game.PauseButton)
+}
+
+// Nothing in the game advances while paused since `game.Update` is not called
+func (s *Gameplay) pause(m *Manager) {
+	m.AudioPlayer.SetMusicVolume(float64(m.Settings.MusicVolume) / MAX_VOLUME * PAUSE_MUSIC_VOLUME_RATIO)
+	m.Switch(NewPause(s))
+}
+
+func (s *Gameplay) resume(m *Manager) {
+	m.ApplySettings()
+	m.Switch(s)
+}
+
+// Leave the game and go back to the mode select
+func (s *Gameplay) quit(m *Manager) {
+	if err := m.Close(); err != nil {
+		log.Println("could not save replay: ", err)
+	}
+	m.game = nil
+	m.ApplySettings()
+	m.Switch(NewModeSelect())
+}
+
+const (
+	pauseItemResume = iota
+	pauseItemRetry
+	pauseItemQuit
+)
+
+// Pause stops the game and shows the menu instead of the board so that the player cannot peek at it
+type Pause struct {
+	gameplay *Gameplay
+	menu     Menu
+}
+
+func NewPause(gameplay *Gameplay) *Pause {
+	return &Pause{
+		gameplay: gameplay,
+		menu:     Menu{Title: "PAUSE", Items: []string{"Resume", "Retry", "Quit to Menu"}},
+	}
+}
+
+func (s *Pause) Update(m *Manager) error {
+	if isPausePressed(m) {
+		s.gameplay.resume(m)
+		return nil
+	}
+	if !s.menu.Update() {
+		return nil
+	}
+	switch s.menu.Cursor {
+	case pauseItemResume:
+		s.gameplay.resume(m)
+	case pauseItemRetry:
+		s.gameplay.game.Restart()
+		s.gameplay.resume(m)
+	case pauseItemQuit:
+		s.gameplay.quit(m)
+	}
+	return nil
+}
+
+func (s *Pause) Draw(screen *ebiten.Image) {
+	screen.Fill(game.BACKGROUND_COLOR)
+	s.menu.Draw(screen)
+	drawHint(screen, "↑↓ : Select    Enter : OK    Esc/P : Resume")
+}
+
+// Results waits for the player to retry or leave after the game is finished.
+// The results themselves are drawn by the game.
+type Results struct {
+	gameplay *Gameplay
+}
+
+func NewResults(gameplay *Gameplay) *Results {
+	return &Results{gameplay: gameplay}
+}
+
+func (s *Results) Update(m *Manager) error {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		s.gameplay.game.Restart()
+		m.Switch(s.gameplay)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		s.gameplay.quit(m)
+	}
+	return nil
+}
+
+func (s *Results) Draw(screen *ebiten.Image) {
+	s.gameplay.Draw(screen)
+}
@@ -0,0 +1,116 @@
+package scene
+
+import (
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/okayama-daiki/tetris/tetris/audio"
+	"github.com/okayama-daiki/tetris/tetris/engine"
+	"github.com/okayama-daiki/tetris/tetris/game"
+	"github.com/okayama-daiki/tetris/tetris/replay"
+)
+
+const (
+	SCREEN_WIDTH  = 600
+	SCREEN_HEIGHT = 600
+)
+
+// Scene is a screen of the application such as the title or the gameplay
+type Scene interface {
+	Update(m *Manager) error
+	Draw(screen *ebiten.Image)
+}
+
+// Manager implements `ebiten.Game` by delegating to the current scene
+type Manager struct {
+	AudioPlayer *audio.Player
+	Settings    Settings
+	Gamepads    *game.Gamepads
+	Seed        int64               // Passed to the games started from the menu
+	ReplayPath  string              // Passed to the games started from the menu
+	Kicks       *engine.KickTables  // Custom kick tables of SRS passed to the games, if any
+	Attack      *engine.AttackTable // Custom attack table passed to the games, if any
+	current     Scene
+	game        *game.Game // The game being played, if any
+}
+
+func NewManager(audioPlayer *audio.Player) *Manager {
+	settings := LoadSettings()
+	m := &Manager{
+		AudioPlayer: audioPlayer,
+		Settings:    settings,
+		Gamepads:    game.NewGamepads(settings.GamepadBindings),
+		current:     NewTitle(),
+	}
+	m.ApplySettings()
+	return m
+}
+
+func (m *Manager) Switch(scene Scene) {
+	m.current = scene
+}
+
+// StartGame starts a new game of the mode and switches to the gameplay
+func (m *Manager) StartGame(mode string) error {
+	rules := m.Settings.RulesFor(mode)
+	rules.Kicks = m.Kicks
+	rules.Attack = m.Attack
+	g, err := game.NewGame(m.AudioPlayer, m.Seed, mode, m.Settings.Handling, rules)
+	if err != nil {
+		return err
+	}
+	g.ReplayPath = m.ReplayPath
+	g.KeyBindings = m.Settings.KeyBindings
+	g.Gamepads = m.Gamepads
+	g.Previews = m.Settings.Previews
+	m.play(g)
+	return nil
+}
+
+// PlayReplay plays back the replay and switches to the gameplay
+func (m *Manager) PlayReplay(r *replay.Replay) error {
+	g, err := game.NewReplayGame(m.AudioPlayer, r)
+	if err != nil {
+		return err
+	}
+	g.KeyBindings = m.Settings.KeyBindings
+	g.Gamepads = m.Gamepads
+	g.Previews = m.Settings.Previews
+	m.play(g)
+	return nil
+}
+
+func (m *Manager) play(g *game.Game) {
+	if err := m.Close(); err != nil {
+		log.Println("could not save replay: ", err)
+	}
+	m.game = g
+	m.Switch(NewGameplay(g))
+}
+
+// Close saves the replay of the game being played
+func (m *Manager) Close() error {
+	if m.game == nil {
+		return nil
+	}
+	return m.game.SaveReplay()
+}
+
+func (m *Manager) ApplySettings() {
+	m.AudioPlayer.SetMusicVolume(float64(m.Settings.MusicVolume) / MAX_VOLUME)
+	m.AudioPlayer.SetSoundVolume(float64(m.Settings.SoundVolume) / MAX_VOLUME)
+}
+
+func (m *Manager) Update() error {
+	m.AudioPlayer.Update()
+	m.Gamepads.Update()
+	return m.current.Update(m)
+}
+
+func (m *Manager) Draw(screen *ebiten.Image) {
+	m.current.Draw(screen)
+}
+
+func (m *Manager) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
+	return SCREEN_WIDTH, SCREEN_HEIGHT
+}
@@ -0,0 +1,75 @@
+package scene
+
+import (
+	"github.com/hajimehoshi/bitmapfont/v3"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	KEY_REPEAT_WAIT_TIME = 15
+	KEY_REPEAT_INTERVAL  = 4
+	// The menu scrolls when it has more items than this
+	MAX_VISIBLE_ITEMS = 10
+)
+
+var fontFace = text.NewGoXFace(bitmapfont.Face)
+
+// Return true if the key is just pressed or held long enough to repeat
+func isRepeated(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || d > KEY_REPEAT_WAIT_TIME && d%KEY_REPEAT_INTERVAL == 0
+}
+
+// Menu is a vertical list of items navigated by up and down keys
+type Menu struct {
+	Title  string
+	Items  []string
+	Cursor int
+	top    int // The first item shown
+}
+
+// Update moves the cursor and returns true if the item under the cursor is chosen by Enter
+func (m *Menu) Update() bool {
+	if len(m.Items) == 0 {
+		return false
+	}
+	if isRepeated(ebiten.KeyUp) {
+		m.Cursor = (m.Cursor + len(m.Items) - 1) % len(m.Items)
+	}
+	if isRepeated(ebiten.KeyDown) {
+		m.Cursor = (m.Cursor + 1) % len(m.Items)
+	}
+	m.top = min(max(m.top, m.Cursor-MAX_VISIBLE_ITEMS+1), m.Cursor)
+	return inpututil.IsKeyJustPressed(ebiten.KeyEnter)
+}
+
+func (m *Menu) Draw(screen *ebiten.Image) {
+	drawText(screen, m.Title, 150, 150, 2)
+	for i, item := range m.Items[m.top:min(m.top+MAX_VISIBLE_ITEMS, len(m.Items))] {
+		marker := "  "
+		if m.top+i == m.Cursor {
+			marker = "> "
+		}
+		drawText(screen, marker+item, 150, 230+float64(i)*30, 1)
+	}
+	if m.top > 0 {
+		drawText(screen, "  ↑", 150, 205, 1)
+	}
+	if m.top+MAX_VISIBLE_ITEMS < len(m.Items) {
+		drawText(screen, "  ↓", 150, 230+MAX_VISIBLE_ITEMS*30, 1)
+	}
+}
+
+func drawText(screen *ebiten.Image, s string, x, y, scale float64) {
+	option := &text.DrawOptions{LayoutOptions: text.LayoutOptions{LineSpacing: 20}}
+	option.GeoM.Scale(scale, scale)
+	option.GeoM.Translate(x, y)
+	text.Draw(screen, s, fontFace, option)
+}
+
+// Draw the key guide at the bottom of the screen
+func drawHint(screen *ebiten.Image, hint string) {
+	drawText(screen, hint, 30, SCREEN_HEIGHT-40, 1)
+}
@@ -0,0 +1,327 @@
+package scene
+
+import (
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/okayama-daiki/tetris/tetris/engine"
+	"github.com/okayama-daiki/tetris/tetris/game"
+	"github.com/okayama-daiki/tetris/tetris/storage"
+)
+
+const (
+	SETTINGS_FILE           = "settings.json"
+	MAX_VOLUME              = 10
+	MAX_HANDLING_FRAMES     = 30
+	MAX_DELAY_FRAMES        = 60
+	MAX_SOFT_DROP_FACTOR    = 40
+	INFINITE_SOFT_DROP_TEXT = "Infinite"
+	MAX_LOCK_RESETS         = 30
+	MESSINESS_STEP          = 10
+	// Only a guard against absurd values in the settings file, since the next panel shrinks any number of previews to fit
+	MAX_PREVIEWS = 99
+)
+
+// Settings are the user's preferences kept across sessions
+type Settings struct {
+	MusicVolume int              `json:"music_volume"`
+	SoundVolume int              `json:"sound_volume"`
+	Handling    engine.Handling  `json:"handling"`
+	Rules       engine.Rules     `json:"rules"`
+	Previews    int              `json:"previews"`
+	KeyBindings game.KeyBindings `json:"key_bindings"`
+	// Bindings of each gamepad by its SDL ID
+	GamepadBindings map[string]game.GamepadBindings `json:"gamepad_bindings"`
+	// Rules chosen for each mode by its name, which override `Rules`
+	ModeRules map[string]*ModeRules `json:"mode_rules"`
+	mode      string                // The mode whose rules are edited on the settings screen
+}
+
+// ModeRules are the rules which are chosen for each mode, since the modes are played at different speeds
+type ModeRules struct {
+	Gravity    string `json:"gravity"`     // One of `engine.GravityNames`
+	LockDown   string `json:"lock_down"`   // One of `engine.LockDownNames`
+	LockDelay  int    `json:"lock_delay"`  // Frames for which a mino stays on the stack before it locks
+	LockResets int    `json:"lock_resets"` // Moves and rotations which reset the lock delay under the extended placement
+}
+
+// Return the rules of the mode, which start from the rules for all modes if they have not been chosen yet
+func (s *Settings) modeRules(mode string) *ModeRules {
+	if s.ModeRules == nil {
+		s.ModeRules = map[string]*ModeRules{}
+	}
+	rules, ok := s.ModeRules[mode]
+	if !ok || rules == nil {
+		rules = &ModeRules{
+			Gravity:    s.Rules.Gravity,
+			LockDown:   s.Rules.LockDown,
+			LockDelay:  s.Rules.LockDelay,
+			LockResets: s.Rules.LockResets,
+		}
+		s.ModeRules[mode] = rules
+	}
+	return rules
+}
+
+// RulesFor returns the rules of the games of the mode
+func (s *Settings) RulesFor(mode string) engine.Rules {
+	rules := s.Rules
+	modeRules := s.modeRules(mode)
+	rules.Gravity = modeRules.Gravity
+	rules.LockDown = modeRules.LockDown
+	rules.LockDelay = modeRules.LockDelay
+	rules.LockResets = modeRules.LockResets
+	return rules
+}
+
+func DefaultSettings() Settings {
+	return Settings{
+		MusicVolume:     MAX_VOLUME,
+		SoundVolume:     MAX_VOLUME,
+		Handling:        engine.DefaultHandling(),
+		Rules:           engine.DefaultRules(),
+		Previews:        game.DEFAULT_PREVIEWS,
+		KeyBindings:     game.DefaultKeyBindings(),
+		GamepadBindings: map[string]game.GamepadBindings{},
+		ModeRules:       map[string]*ModeRules{},
+		mode:            engine.ModeNames[0],
+	}
+}
+
+// LoadSettings returns the saved settings, or the default ones if there are none
+func LoadSettings() Settings {
+	settings := DefaultSettings()
+	if err := storage.Load(SETTINGS_FILE, &settings); err != nil {
+		log.Println("could not load settings: ", err)
+	}
+	if settings.GamepadBindings == nil {
+		settings.GamepadBindings = map[string]game.GamepadBindings{}
+	}
+	// The file may have been edited by hand, so bring every value of every mode into the range the settings screen allows
+	for _, mode := range engine.ModeNames {
+		settings.mode = mode
+		for _, item := range settingItems {
+			item.change(&settings, 0)
+		}
+	}
+	settings.mode = engine.ModeNames[0]
+	return settings
+}
+
+// An item of the settings screen which is changed by left and right keys
+type settingItem struct {
+	label string
+	value func(s *Settings) string
+	// change moves the value by `delta` steps within its range, so 0 only brings the value into the range
+	change func(s *Settings, delta int)
+}
+
+func volumeItem(label string, volume func(s *Settings) *int) settingItem {
+	return settingItem{
+		label: label,
+		value: func(s *Settings) string {
+			return fmt.Sprintf("%d", *volume(s))
+		},
+		change: func(s *Settings, delta int) {
+			*volume(s) = min(max(*volume(s)+delta, 0), MAX_VOLUME)
+		},
+	}
+}
+
+func framesItem(label string, maxFrames int, frames func(s *Settings) *int) settingItem {
+	return settingItem{
+		label: label,
+		value: func(s *Settings) string {
+			return fmt.Sprintf("%d F", *frames(s))
+		},
+		change: func(s *Settings, delta int) {
+			*frames(s) = min(max(*frames(s)+delta, 0), maxFrames)
+		},
+	}
+}
+
+func countItem(label string, maxCount int, count func(s *Settings) *int) settingItem {
+	return settingItem{
+		label: label,
+		value: func(s *Settings) string {
+			return fmt.Sprintf("%d", *count(s))
+		},
+		change: func(s *Settings, delta int) {
+			*count(s) = min(max(*count(s)+delta, 0), maxCount)
+		},
+	}
+}
+
+// The soft drop factor goes up from 1 to `MAX_SOFT_DROP_FACTOR` and then to infinite
+var softDropItem = settingItem{
+	label: "Soft Drop",
+	value: func(s *Settings) string {
+		if s.Handling.SoftDropFactor == engine.INFINITE_SOFT_DROP {
+			return INFINITE_SOFT_DROP_TEXT
+		}
+		return fmt.Sprintf("x%d", s.Handling.SoftDropFactor)
+	},
+	change: func(s *Settings, delta int) {
+		factor := s.Handling.SoftDropFactor
+		if factor == engine.INFINITE_SOFT_DROP {
+			factor = MAX_SOFT_DROP_FACTOR + 1
+		}
+		factor = min(max(factor+delta, 1), MAX_SOFT_DROP_FACTOR+1)
+		if factor > MAX_SOFT_DROP_FACTOR {
+			factor = engine.INFINITE_SOFT_DROP
+		}
+		s.Handling.SoftDropFactor = factor
+	},
+}
+
+// The number of previews goes up from 0, which hides them for training
+var previewsItem = settingItem{
+	label: "Previews",
+	value: func(s *Settings) string {
+		if s.Previews == 0 {
+			return "Off"
+		}
+		return fmt.Sprintf("%d", s.Previews)
+	},
+	change: func(s *Settings, delta int) {
+		s.Previews = min(max(s.Previews+delta, 0), MAX_PREVIEWS)
+	},
+}
+
+// The start level goes from 1 to the last level of Marathon so that every game has a level to complete
+var startLevelItem = settingItem{
+	label: "Start Level",
+	value: func(s *Settings) string {
+		return fmt.Sprintf("%d", s.Rules.StartLevel)
+	},
+	change: func(s *Settings, delta int) {
+		s.Rules.StartLevel = min(max(s.Rules.StartLevel+delta, 1), engine.MARATHON_LEVELS)
+	},
+}
+
+// The cheese race has at least one garbage row and leaves the top of the field free
+var cheeseHeightItem = settingItem{
+	label: "Cheese Height",
+	value: func(s *Settings) string {
+		return fmt.Sprintf("%d", s.Rules.CheeseHeight)
+	},
+	change: func(s *Settings, delta int) {
+		s.Rules.CheeseHeight = min(max(s.Rules.CheeseHeight+delta, 1), engine.MAX_CHEESE_HEIGHT)
+	},
+}
+
+// The messiness of the cheese race goes by `MESSINESS_STEP` percent
+var cheeseMessinessItem = settingItem{
+	label: "Cheese Messiness",
+	value: func(s *Settings) string {
+		return fmt.Sprintf("%d%%", s.Rules.CheeseMessiness)
+	},
+	change: func(s *Settings, delta int) {
+		s.Rules.CheeseMessiness = min(max(s.Rules.CheeseMessiness+delta*MESSINESS_STEP, 0), 100)
+	},
+}
+
+// An item which cycles through the names
+func choiceItem(label string, names []string, choice func(s *Settings) *string) settingItem {
+	return settingItem{
+		label: label,
+		value: func(s *Settings) string {
+			return *choice(s)
+		},
+		change: func(s *Settings, delta int) {
+			i := max(slices.Index(names, *choice(s)), 0)
+			*choice(s) = names[(i+delta+len(names))%len(names)]
+		},
+	}
+}
+
+// An item which switches the rule on and off
+func toggleItem(label string, toggle func(s *Settings) *bool) settingItem {
+	return settingItem{
+		label: label,
+		value: func(s *Settings) string {
+			if *toggle(s) {
+				return "On"
+			}
+			return "Off"
+		},
+		change: func(s *Settings, delta int) {
+			if delta != 0 {
+				*toggle(s) = !*toggle(s)
+			}
+		},
+	}
+}
+
+var settingItems = []settingItem{
+	volumeItem("Music Volume", func(s *Settings) *int { return &s.MusicVolume }),
+	volumeItem("Sound Volume", func(s *Settings) *int { return &s.SoundVolume }),
+	framesItem("DAS", MAX_HANDLING_FRAMES, func(s *Settings) *int { return &s.Handling.DAS }),
+	framesItem("ARR", MAX_HANDLING_FRAMES, func(s *Settings) *int { return &s.Handling.ARR }),
+	softDropItem,
+	framesItem("DAS Cut", MAX_HANDLING_FRAMES, func(s *Settings) *int { return &s.Handling.DASCut }),
+	framesItem("DCD", MAX_HANDLING_FRAMES, func(s *Settings) *int { return &s.Handling.DCD }),
+	previewsItem,
+	choiceItem("Rotation", engine.RotationSystemNames, func(s *Settings) *string { return &s.Rules.RotationSystem }),
+	choiceItem("180 Kicks", engine.Kick180Names, func(s *Settings) *string { return &s.Rules.Kick180 }),
+	choiceItem("Randomizer", engine.RandomizerNames, func(s *Settings) *string { return &s.Rules.Randomizer }),
+	toggleItem("IRS", func(s *Settings) *bool { return &s.Rules.IRS }),
+	toggleItem("IHS", func(s *Settings) *bool { return &s.Rules.IHS }),
+	toggleItem("All Spin", func(s *Settings) *bool { return &s.Rules.AllSpin }),
+	framesItem("ARE", MAX_DELAY_FRAMES, func(s *Settings) *int { return &s.Rules.ARE }),
+	framesItem("Line Clear Delay", MAX_DELAY_FRAMES, func(s *Settings) *int { return &s.Rules.LineClearDelay }),
+	startLevelItem,
+	choiceItem("Level Goal", engine.LevelGoalNames, func(s *Settings) *string { return &s.Rules.LevelGoal }),
+	cheeseHeightItem,
+	cheeseMessinessItem,
+	countItem("Garbage Delay", engine.MAX_GARBAGE_DELAY, func(s *Settings) *int { return &s.Rules.GarbageDelay }),
+	// The items below the mode change only the rules of the mode
+	choiceItem("Rules For", engine.ModeNames, func(s *Settings) *string { return &s.mode }),
+	choiceItem("Gravity", engine.GravityNames, func(s *Settings) *string { return &s.modeRules(s.mode).Gravity }),
+	choiceItem("Lock Down", engine.LockDownNames, func(s *Settings) *string { return &s.modeRules(s.mode).LockDown }),
+	framesItem("Lock Delay", MAX_DELAY_FRAMES, func(s *Settings) *int { return &s.modeRules(s.mode).LockDelay }),
+	countItem("Lock Resets", MAX_LOCK_RESETS, func(s *Settings) *int { return &s.modeRules(s.mode).LockResets }),
+}
+
+// SettingsScene edits `Manager.Settings` and saves them when leaving
+type SettingsScene struct {
+	menu Menu
+}
+
+func NewSettingsScene() *SettingsScene {
+	return &SettingsScene{menu: Menu{Title: "SETTINGS"}}
+}
+
+func (s *SettingsScene) Update(m *Manager) error {
+	item := settingItems[s.menu.Cursor]
+	switch {
+	case isRepeated(ebiten.KeyLeft):
+		item.change(&m.Settings, -1)
+		m.ApplySettings()
+	case isRepeated(ebiten.KeyRight), inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		item.change(&m.Settings, 1)
+		m.ApplySettings()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		if err := storage.Save(SETTINGS_FILE, m.Settings); err != nil {
+			log.Println("could not save settings: ", err)
+		}
+		m.Switch(NewTitle())
+		return nil
+	}
+
+	s.menu.Items = s.menu.Items[:0]
+	for _, item := range settingItems {
+		s.menu.Items = append(s.menu.Items, fmt.Sprintf("%-16s: %s", item.label, item.value(&m.Settings)))
+	}
+	s.menu.Update()
+	return nil
+}
+
+func (s *SettingsScene) Draw(screen *ebiten.Image) {
+	screen.Fill(game.BACKGROUND_COLOR)
+	s.menu.Draw(screen)
+	drawHint(screen, "←→ : Change    Esc : Back")
+}
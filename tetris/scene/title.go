@@ -0,0 +1,74 @@
+package scene
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/okayama-daiki/tetris/tetris/engine"
+	"github.com/okayama-daiki/tetris/tetris/game"
+)
+
+const (
+	titleItemPlay = iota
+	titleItemSettings
+	titleItemControls
+	titleItemQuit
+)
+
+type Title struct {
+	menu Menu
+}
+
+func NewTitle() *Title {
+	return &Title{menu: Menu{Title: "EBITETRIS", Items: []string{"Play", "Settings", "Controls", "Quit"}}}
+}
+
+func (s *Title) Update(m *Manager) error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		return ebiten.Termination
+	}
+	if !s.menu.Update() {
+		return nil
+	}
+	switch s.menu.Cursor {
+	case titleItemPlay:
+		m.Switch(NewModeSelect())
+	case titleItemSettings:
+		m.Switch(NewSettingsScene())
+	case titleItemControls:
+		m.Switch(NewControls())
+	case titleItemQuit:
+		return ebiten.Termination
+	}
+	return nil
+}
+
+func (s *Title) Draw(screen *ebiten.Image) {
+	screen.Fill(game.BACKGROUND_COLOR)
+	s.menu.Draw(screen)
+	drawHint(screen, "↑↓ : Select    Enter : OK    Esc : Quit")
+}
+
+type ModeSelect struct {
+	menu Menu
+}
+
+func NewModeSelect() *ModeSelect {
+	return &ModeSelect{menu: Menu{Title: "MODE", Items: engine.ModeNames}}
+}
+
+func (s *ModeSelect) Update(m *Manager) error {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		m.Switch(NewTitle())
+		return nil
+	}
+	if s.menu.Update() {
+		return m.StartGame(engine.ModeNames[s.menu.Cursor])
+	}
+	return nil
+}
+
+func (s *ModeSelect) Draw(screen *ebiten.Image) {
+	screen.Fill(game.BACKGROUND_COLOR)
+	s.menu.Draw(screen)
+	drawHint(screen, "↑↓ : Select    Enter : Start    Esc : Back")
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+const (
+	DIRECTORY_NAME = "ebitetris"
+)
+
+// Return the path of the file in the user's config directory
+func Path(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DIRECTORY_NAME, name), nil
+}
+
+// Load decodes the JSON file into `v`.
+// If the file does not exist yet, `v` is left untouched and no error is returned.
+func Load(name string, v any) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Save encodes `v` into the JSON file, creating the directory if needed
+func Save(name string, v any) error {
+	path, err := Path(name)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
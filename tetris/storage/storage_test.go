@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	type record struct {
+		Name  string
+		Value int
+	}
+
+	got := record{Name: "untouched"}
+	if err := Load("test.json", &got); err != nil || got.Name != "untouched" {
+		t.Fatalf("got %v and %v, want the untouched value", got, err)
+	}
+
+	want := record{Name: "sprint", Value: 42}
+	if err := Save("test.json", want); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load("test.json", &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}